@@ -12,13 +12,44 @@ https://vuln.go.dev. Requests to the vulnerability database contain only module
 paths with vulnerabilities already known to the database, not code or other
 properties of your program. See https://vuln.go.dev/privacy.html for more.
 Use the -db flag to specify a different database, which must implement the
-specification at https://go.dev/security/vuln/database.
+specification at https://go.dev/security/vuln/database. To check that a
+database conforms to the OSV schema understood by govulncheck, use the
+-strict-osv flag, which makes govulncheck fail on OSV entries with unknown
+fields instead of ignoring those fields. Conversely, the -emit-osv flag makes
+JSON output include the OSV entries exactly as read from the database, so that
+fields unknown to govulncheck are passed on to consumers of the output.
+When running many scans in a row, such as of hundreds of binaries, pass
+'-db-rate N' to make at most N requests per second to the database, so that
+the scans are not blocked by its rate limits.
+
+Since the database changes over time, scanning the same code again can give
+different results. The -export-db flag writes the database entries consulted by
+a scan to a directory, and the -db-snapshot flag makes govulncheck use only
+such a snapshot, which gives reproducible results for audits at a point in
+time.
+
+Module level scans ('-scan module') of the same dependencies give the same
+results until the database changes. The -cache-dir flag stores these results in
+a directory, keyed by the go.mod and go.sum files, the Go version, and the last
+modification time of the database, and reuses them when all of these are
+unchanged. This saves work in continuous integration setups that scan the same
+dependencies many times a day. Results of modules in a go.work workspace are not
+cached.
 
 Govulncheck looks for vulnerabilities in Go programs using a specific build
 configuration. For analyzing source code, that configuration is the Go version
-specified by the “go” command found on the PATH. For binaries, the build
-configuration is the one used to build the binary. Note that different build
-configurations may have different known vulnerabilities.
+the module is built with, as given by the toolchain or else the go directive
+of its go.mod file, or the version of the “go” command found on the PATH if
+go.mod specifies neither. The GOVERSION environment variable overrides both.
+For binaries, the build configuration is the one used to build the binary.
+Note that different build configurations may have different known
+vulnerabilities.
+
+Build tools that projects track as imports of a file with the "tools" build tag,
+conventionally tools.go, are not part of the code that ships, but their
+vulnerabilities matter for the build environment. The -tools flag makes
+govulncheck scan these tools instead of the packages matching the patterns, and
+labels the results as those of build tools.
 
 # Usage
 
@@ -37,12 +68,155 @@ For example, it might say
 
 To control which files are processed, use the -tags flag to provide a
 comma-separated list of build tags, and the -test flag to indicate that test
-files should be included.
+files should be included. The -exclude-tests flag ensures that test files are
+not analyzed, and takes precedence over -test. Govulncheck warns when the
+scanned packages import vulnerable packages but have no entry functions, such as
+when the build tags exclude the main function of a main package or the exported
+functions of a library, since the calls of the excluded code are then not
+analyzed.
+
+Govulncheck also scans the modules of a Go workspace, as selected by a go.work
+file or the GOWORK environment variable, in a single run. At the root of a
+workspace, the pattern './...' matches the packages of every workspace module
+below it, and module level scans without patterns cover all these modules. The
+traces of findings start in the packages of the workspace module that uses the
+vulnerable code.
+
+Govulncheck requires a go.mod file. For legacy code without one, the '-gopath'
+flag loads packages in GOPATH mode instead and maps them to modules on a
+best-effort basis: the module of a package is the longest prefix of its import
+path that the module proxy, as configured for the go command, knows as a module,
+and its version is the semantic version tag of the commit checked out in GOPATH,
+if any. Modules whose version cannot be determined this way are not checked.
+
+Govulncheck fails on packages with build or type errors, since their results
+could be incomplete and misleadingly clean. To scan them anyway, pass
+'-allow-errors': the errors are then printed as warnings and, as call analysis
+requires well-typed code, the scan is done at package level.
+Similarly, if the call graph of well-typed code cannot be built, govulncheck
+warns and does the scan at package level, reporting the vulnerable packages
+imported and modules required by the code. In JSON output, the progress message
+of the warning then has symbols_failed set.
+
+For a quick check of the code of the packages themselves, pass '-depth direct'.
+Govulncheck then only reports vulnerabilities in the packages matched by the
+patterns and in the packages they import directly, and in the modules of these
+packages, ignoring deeper dependencies.
+
+Code generators, for protobuf and gRPC for instance, mark the files they write
+with a '// Code generated ... DO NOT EDIT.' comment. Call stacks passing through
+functions of such files in the scanned packages are marked as going through
+generated code, and their frames are marked as generated in JSON output. Pass
+'-hide-generated' to report only the vulnerable symbols that are also reachable
+without going through generated code.
+
+For gating builds that fail on any called vulnerability, pass '-fail-fast'.
+Govulncheck then stops at the first called vulnerability, by OSV ID, and reports
+only it: the calls and traces of the other vulnerabilities are not analyzed, nor
+are the packages importing them reported. It exits with status 3 as usual.
+
+Analyzing the calls of many vulnerabilities can take a while. Pass '-stream'
+to have the calls of each vulnerability analyzed separately, in order of OSV
+ID, and each called vulnerability reported as soon as its calls and traces are
+found: text output then prints a line for it before the full report, and JSON
+output contains its findings right away.
+
+By default, the main and init functions of main packages, and the exported
+functions and methods of other packages, are the entry points of the call
+analysis. To audit a library, pass '-as-library'. Only the exported functions
+and methods of the packages of the main module that other modules can import
+are then entry points, so that the results show what importers of the library
+could reach. Vulnerable symbols called only from main packages, or from internal
+packages that no such function reaches, are then reported at package level.
+
+A vulnerability is reported at the most precise level the scan confirms: a
+vulnerable module version is required, a vulnerable package is imported, or a
+vulnerable symbol is called. To see why each vulnerability was reported at its
+level, pass '-explain'. Text output then lists the vulnerabilities of every
+level, each with the steps that led to its level, such as
+
+	module golang.org/x/text@v0.3.0 at vulnerable version → package
+	golang.org/x/text/language imported → symbol language.Parse not reached in
+	call graph → reported at package level
+
+To document the vulnerabilities that were checked and found not to be called,
+for instance in a VEX statement that the code is not affected, pass
+'-list-unreachable'. Text output then ends with a list of the vulnerabilities
+found in imported packages but not called, and of those found in required
+modules whose vulnerable packages are not imported, with the module versions
+they were found in. It cannot be used with '-fail-fast', which does not analyze
+the calls of all vulnerabilities.
+
+On code with many vulnerabilities, '-max-results N' shows only the first N,
+the called ones before the imported and required ones, followed by a note of how
+many more were found. The summary and the exit code still account for all of
+them, and '-json' gives the complete list.
 
 To include more detailed stack traces, pass '-show traces', this will cause it to
-print the full call stack for each entry.
+print the full call stack for each entry. Long call stacks can be shortened with
+'-max-stack-depth N', which keeps N frames from each end of every stack shown.
+Passing '-show panic' instead prints the full call stacks in the format of the
+stack traces of a Go panic, starting with the vulnerable symbol, so that tools
+parsing such traces can be used on them. Frames show absolute file names and,
+as source analysis has no program counters, a +0x0 offset.
+
+Each trace shows a single representative call stack, the shortest one with the
+fewest calls of interface methods and function values. Pass '-stacks N' to show
+up to N call stacks of each vulnerable symbol, all of the same shortest length,
+from different entry functions.
+
+Traces do not show every call stack. To debug a suspected false positive, pass
+'-print-reachable-functions' to also list all the entry functions of your code
+from which the vulnerable symbol is reachable. Their number is always reported,
+in text output with '-show verbose' and in the entry_point_count field of JSON
+findings. A vulnerable symbol reachable from many entry functions is likely more
+entangled with your code, and harder to avoid, than one reachable from a single
+one.
+
+Descriptions and summaries in text output are wrapped to the width of the
+terminal, or to 80 characters when the output is not a terminal. Pass
+'-width N' to wrap them to N characters instead.
+
+Each vulnerability in text and HTML output links to more information, at the
+URL given by the database or else at pkg.go.dev. For databases whose advisories
+are hosted elsewhere, pass '-advisory-url-template' with a URL in which {id}
+stands for the vulnerability ID, such as
+https://security.example.com/advisories/{id}.
+
+Text output starts with the main module, as in "Scanning module
+example.com/m...", so that saved reports identify the module they are for.
 
 To include progress messages and more details on findings, pass '-show verbose'.
+Warnings, such as those about packages with errors or expired overrides, are
+shown regardless; JSON output reports them as progress messages starting with
+"warning: ".
+Verbose output starts with the settings of the scan, such as the main module,
+Go version, database, build tags, and platform, which JSON output records in its
+config message, so that saved reports identify the code they are for.
+Vulnerabilities are then also shown with their CVE and GHSA aliases and, if the
+database records them, the CWE IDs of their weaknesses, as found in the
+'cwe_ids' list of the 'database_specific' field of OSV entries. Each
+reference of type FIX, such as a link to the commit fixing a vulnerability, is
+shown on a separate 'Fix:' line.
+
+To print the versions of govulncheck, of the Go toolchain, and of the
+vulnerability database, along with the database URL, and exit without scanning,
+pass '-version'. The information is printed as text, so '-version' cannot be
+combined with other output formats. Passing '-show version' instead prints the
+same information before the results of the scan.
+
+To find out where a slow scan spends its time, pass '-timings'. Text output then
+ends with the wall-clock time of each phase of the scan, such as loading
+packages, fetching vulnerabilities, and building the call graph, and JSON output
+contains a timing message for each phase as it completes. Some phases run
+concurrently, so their times can add up to more than the total.
+
+To profile govulncheck itself, pass '-cpuprofile file' or '-memprofile file'.
+They write a CPU profile of the whole run, and a heap profile taken at its end,
+in the format read by 'go tool pprof':
+
+	$ govulncheck -cpuprofile cpu.out ./...
+	$ go tool pprof cpu.out
 
 To run govulncheck on a compiled binary, pass it the path to the binary file
 with the '-mode binary' flag:
@@ -55,6 +229,121 @@ also the main module of the binary. The latter functions are checked for only
 when the precise version of the binary module is known. Govulncheck output on
 binaries omits call stacks, which require source code analysis.
 
+Without call stacks, a vulnerable symbol in a binary is only known to be
+present, and JSON findings for it have reachability "present". The linker
+removes most unreachable code, but not all of it, so these findings can be false
+positives. To leave them out, scan the binary at package level with
+'-scan package'.
+
+A binary inside a tar or zip archive, such as a release download, can be scanned
+without unpacking the archive by joining the path of the archive and the path of
+the binary within it with '//':
+
+	$ govulncheck -mode binary dist/my-go-program.tar.gz//bin/my-go-program
+
+Archives are recognized by their .tar, .tar.gz, .tgz, or .zip extension.
+
+The JSON output of a previous run, produced with '-format json', can be rendered
+as the standard text report without scanning again by passing the saved file to
+the '-render' flag:
+
+	$ govulncheck -format json ./... > result.json
+	$ govulncheck -render result.json
+
+To look up a vulnerability in the database without scanning, for instance a CVE
+reported by another tool, pass its Go vulnerability ID or one of its CVE or GHSA
+aliases to the '-id' flag. Govulncheck prints the matching entry with the modules
+it affects and the versions that fix them:
+
+	$ govulncheck -id CVE-2021-42248
+
+Text output can be replaced by a custom report rendered with a Go template
+(see [text/template]) read from the file passed to the '-template' flag. The
+template is executed once the scan is complete, with a value that has the
+following fields:
+
+	Config         *govulncheck.Config     the configuration of the scan
+	SBOM           *govulncheck.SBOM       the modules of the scanned code, if known
+	OSVs           []*osv.Entry            the OSV entries of the modules scanned
+	Findings       []*govulncheck.Finding  the vulnerability findings
+	SymbolsFailed  bool                    whether the call graph could not be built,
+	                                       in which case Config has scan level package
+
+These types are those of the JSON output, described in
+[golang.org/x/vuln/internal/govulncheck]. In addition to the built-in template
+functions, templates can use 'osv ID', which returns the OSV entry with the
+given ID, 'inc N', which returns N+1, 'indent N S', which indents each line of
+S by N spaces, and 'wrap N S', which word wraps S to lines of at most N
+characters. For example, the following template lists the vulnerable functions
+called by the code:
+
+	{{range .Findings}}{{with index .Trace 0}}{{if .Function}}
+	{{.Package}}.{{.Function}}{{end}}{{end}}{{end}}
+
+For a short summary, the '-top-per-module' flag replaces the report with one
+line per vulnerable module. Each line names the vulnerability found at the most
+precise level, called before imported before required, and the number of other
+vulnerabilities in the module.
+
+For code reviews, '-group-by file' replaces the report with the source files
+that call vulnerable symbols, sorted by name. Each file is followed by the
+vulnerabilities whose compact traces leave the scanned code in that file.
+
+For dependency reviews, the '-list-modules' flag replaces the report with an
+inventory of every module in the scan, one 'path@version' per line sorted by
+path. Modules with known vulnerabilities are followed by the IDs of the
+vulnerabilities found in them and whether each is called, imported, or only
+required by the code, depending on the scan level. In source mode, the modules
+are those of the full build list, as listed by 'go list -m all', including the
+ones providing no package analyzed.
+
+Modules patched locally, for instance with backported fixes, can be listed in a
+file passed with the '-overrides' flag. Each line of the file consists of a
+module path and the lowest version of that module considered fixed, such as
+
+	golang.org/x/text v0.3.0
+
+Govulncheck treats modules at or above that version as not affected by any
+vulnerability, and text output lists the vulnerabilities cleared this way. A
+replaced module can be named by its path or the path of its replacement, and
+the version of the replacement is the one compared.
+For auditing, a line can end with the reason for the override after a #, such as
+
+	golang.org/x/text v0.3.0 # backported fix, accepted until 2024-06, ticket SEC-123
+
+The reason is shown with the vulnerabilities cleared by the override. A date
+following "until", of the form 2024-06 or 2024-06-30, is the expiry date of the
+override: once it has passed, govulncheck warns that the override should be
+revisited, while still applying it.
+
+When the version of a module is wrong, for instance a pseudo-version from a
+build of a modified checkout, its vulnerabilities can be matched against another
+version with '-assume-version module@version', such as
+
+	$ govulncheck -assume-version golang.org/x/text@v0.3.8 ./...
+
+The flag can be repeated for several modules, named by their path or the path of
+their replacement, and accepts Go versions such as go1.21.3 for the standard
+library. The fixed and introduced versions of the findings are those of the
+assumed version, which text output shows next to the version found. Govulncheck
+warns about modules that are not used by the scanned code.
+
+Code built with several Go toolchains, such as a library supporting older Go
+releases, can be checked against the standard library vulnerabilities of all of
+them with the '-min-go' flag. Given a version such as go1.19, govulncheck also
+reports standard library vulnerabilities affecting any Go version from that
+version to the one used for the scan, not just the latter. A vulnerability that
+does not affect the latter is shown as matched as the oldest Go version it
+affects, with the fix for that version.
+
+Modules that are audited by other means can be excluded from the scan entirely
+by listing their paths, one per line, in a file passed with the '-skip-modules'
+flag. Govulncheck does not query the vulnerability database for these modules
+and reports no findings for them, even for vulnerabilities published after the
+modules were audited. Use this flag with care: a skipped module is a blind spot,
+and the list should be reviewed as regularly as the modules it names. The
+skipped modules are recorded in the configuration of the JSON output.
+
 Govulncheck also supports '-mode extract' on a Go binary for extraction of minimal
 information needed to analyze the binary. This will produce a blob, typically much
 smaller than the binary, that can also be passed to govulncheck as an argument with
@@ -72,12 +361,54 @@ Govulncheck supports the Vulnerability EXchange (VEX) output format, following
 the specification at https://github.com/openvex/spec.
 For more details, please see [golang.org/x/vuln/internal/openvex].
 
+For sharing results with people who do not use the command line, '-format html'
+writes a self-contained HTML report, styled inline and without external assets.
+Each vulnerability is a collapsible section with a badge telling whether it is
+called, imported, or only required, a table of the affected modules with their
+found and fixed versions, and the call stacks of the vulnerable symbols.
+
+For keeping an inventory of the advisories that apply to a service,
+'-format sbov' writes a Software Bill of Vulnerabilities: every module of the
+build, including those without known vulnerabilities, with the advisories of the
+database for it and their status, one of called, imported, required, or none
+when the module version is not affected. Storing this document for each scan
+shows how the vulnerability posture of the service changes over time.
+For more details, please see [golang.org/x/vuln/internal/sbov].
+
 # Exit codes
 
 Govulncheck exits successfully (exit code 0) if there are no vulnerabilities,
 and exits unsuccessfully if there are. It also exits successfully if the
-'format -json' ('-json'), '-format sarif', or '-format openvex' is provided,
-regardless of the number of detected vulnerabilities.
+'format -json' ('-json'), '-format sarif', '-format openvex', '-format html', or
+'-format sbov' is provided, regardless of the number of detected vulnerabilities.
+
+A scan that cannot be completed, for instance because the vulnerability
+database cannot be reached, always exits with exit code 1 and reports the
+error on standard error, whatever the output format. In json mode, a zero exit
+code therefore means that the output holds the complete results of the scan.
+
+By default, only vulnerabilities found at the scan level count as detected:
+for instance, a symbol level scan exits unsuccessfully only if vulnerable
+symbols are called. Use '-fail-on package' or '-fail-on module' to also
+exit unsuccessfully when vulnerable packages are merely imported or
+vulnerable modules are merely required, respectively.
+
+The fail level can also be set for some parts of the scanned code only, with a
+file passed with the '-fail-on-modules' flag. Each line of the file consists of
+a package path prefix and a level, such as
+
+	internal/crypto/** module
+	tools/** symbol
+
+The level of the longest prefix matching the package of the scanned code through
+which a vulnerability is found takes precedence over '-fail-on' for that
+vulnerability. That package is the one of the entry function of a call stack,
+or the root package of an import chain. Prefixes are full import paths or paths
+relative to a main module, and match whole path elements, so internal matches
+internal/crypto but not internalx. A trailing /** is allowed and means the same;
+other glob patterns are rejected. Vulnerabilities of required modules and of
+binaries are not found through a package of the scanned code, so only '-fail-on'
+applies to them.
 
 # Limitations
 
@@ -88,14 +419,18 @@ Govulncheck has these limitations:
   - Calls to functions made using package reflect are not visible to static
     analysis. Vulnerable code reachable only through those calls will not be
     reported in source scan mode. Similarly, use of the unsafe package may
-    result in false negatives.
+    result in false negatives, although calls of functions bound to other
+    functions with go:linkname directives are reported.
   - Because Go binaries do not contain detailed call information, govulncheck
     cannot show the call graphs for detected vulnerabilities. It may also
     report false positives for code that is in the binary but unreachable.
   - There is no support for silencing vulnerability findings. See https://go.dev/issue/61211 for
     updates.
-  - Govulncheck reports only standard library vulnerabilities for binaries
-    built with Go versions prior to Go 1.18.
+  - Binaries built with Go versions prior to Go 1.18, and other binaries
+    without module information, such as test binaries of non-main packages
+    (https://go.dev/issue/33976), are only checked for standard library
+    vulnerabilities, and govulncheck warns about it. If such a binary has no
+    Go version either, govulncheck cannot scan it and reports an error.
   - For binaries where the symbol information cannot be extracted, govulncheck
     reports vulnerabilities for all modules on which the binary depends.
 
@@ -89,6 +89,14 @@ func runTestCase(t *testing.T, tcName, testDir string) {
 		// can refer to it. The binary name is unique across all test cases.
 		varName := tcName + "_" + filepath.Base(md) + "_binary"
 		os.Setenv(varName, binary)
+
+		// Build a test binary with "go test -c" as well for
+		// modules that have tests.
+		if tests, _ := filepath.Glob(filepath.Join(md, "*_test.go")); len(tests) > 0 {
+			testBinary, cleanup := test.GoTestBuild(t, md)
+			t.Cleanup(cleanup)
+			os.Setenv(tcName+"_"+filepath.Base(md)+"_testbinary", testBinary)
+		}
 	}
 
 	os.Setenv("moddir", modulesDir)
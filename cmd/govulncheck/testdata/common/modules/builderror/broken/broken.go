@@ -0,0 +1,8 @@
+package broken
+
+import "archive/zip"
+
+func Open() {
+	zip.OpenReader("file.zip")
+	undefined()
+}
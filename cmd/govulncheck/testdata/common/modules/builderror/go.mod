@@ -0,0 +1,3 @@
+module golang.org/builderror
+
+go 1.23
@@ -0,0 +1,15 @@
+module golang.org/generated
+
+go 1.18
+
+require (
+	// This version has vulnerabilities called only from generated code.
+	github.com/tidwall/gjson v1.6.5
+	// This version has a vulnerability called from hand-written code.
+	golang.org/x/text v0.3.0
+)
+
+require (
+	github.com/tidwall/match v1.1.0 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+)
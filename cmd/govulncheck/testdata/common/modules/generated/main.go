@@ -0,0 +1,8 @@
+package main
+
+import "golang.org/x/text/language"
+
+func main() {
+	_, _ = language.Parse("en")
+	_ = (&server{}).Lookup("")
+}
@@ -0,0 +1,7 @@
+module golang.org/library
+
+go 1.18
+
+// This version has a vulnerability that is called
+// from several exported functions.
+require golang.org/x/text v0.3.0
@@ -0,0 +1,20 @@
+package library
+
+import "golang.org/x/text/language"
+
+func ParseTag(s string) (language.Tag, error) {
+	return language.Parse(s)
+}
+
+func MustParseTag(s string) language.Tag {
+	t, err := language.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func DefaultTag() language.Tag {
+	t, _ := language.Parse("en")
+	return t
+}
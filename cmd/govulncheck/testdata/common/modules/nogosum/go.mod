@@ -0,0 +1,6 @@
+module golang.org/nogosum
+
+go 1.18
+
+// There is no go.sum file listing this module.
+require golang.org/x/text v0.3.0
@@ -0,0 +1,11 @@
+package text
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+func Print() {
+	fmt.Println(language.English)
+}
@@ -0,0 +1,3 @@
+module golang.org/tagged
+
+go 1.18
@@ -0,0 +1,7 @@
+//go:build !dev
+
+package main
+
+func main() {
+	run()
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+func main() {
+	tag, err := language.Parse("en")
+	fmt.Println(tag, err)
+}
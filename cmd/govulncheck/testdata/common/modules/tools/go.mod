@@ -0,0 +1,7 @@
+module golang.org/tools
+
+go 1.18
+
+// The code generator, a build tool listed in tools.go,
+// calls a vulnerable symbol of this version.
+require golang.org/x/text v0.3.0
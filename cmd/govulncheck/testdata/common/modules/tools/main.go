@@ -0,0 +1,9 @@
+package main
+
+import "fmt"
+
+//go:generate go run golang.org/tools/cmd/gen
+
+func main() {
+	fmt.Println("hello")
+}
@@ -0,0 +1,7 @@
+//go:build tools
+
+package main
+
+import (
+	_ "golang.org/tools/cmd/gen"
+)
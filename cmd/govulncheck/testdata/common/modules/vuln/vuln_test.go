@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func TestVuln(t *testing.T) {
+	main()
+}
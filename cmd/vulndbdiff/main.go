@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command vulndbdiff reports the differences between two snapshots of
+// a vulnerability database, for reviewing releases of the database.
+//
+// Usage:
+//
+//	vulndbdiff OLD NEW
+//
+// OLD and NEW are local directories holding databases that follow the
+// specification at https://go.dev/security/vuln/database, such as the
+// snapshots written by 'govulncheck -export-db'. For each entry that
+// differs, vulndbdiff prints whether it was added, removed, withdrawn,
+// or modified, followed by the top-level OSV fields that changed with
+// their old and new values in JSON.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/web"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: vulndbdiff OLD NEW\n")
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(context.Background(), os.Stdout, flag.Arg(0), flag.Arg(1)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, w io.Writer, oldDir, newDir string) error {
+	before, err := loadDB(ctx, oldDir)
+	if err != nil {
+		return err
+	}
+	after, err := loadDB(ctx, newDir)
+	if err != nil {
+		return err
+	}
+	for _, c := range diff(before, after) {
+		fmt.Fprintf(w, "%s %s\n", c.kind, c.id)
+		for _, f := range c.fields {
+			fmt.Fprintf(w, "  %s:\n", f.name)
+			if f.old != "" {
+				fmt.Fprintf(w, "    - %s\n", f.old)
+			}
+			if f.new != "" {
+				fmt.Fprintf(w, "    + %s\n", f.new)
+			}
+		}
+	}
+	return nil
+}
+
+// loadDB returns the entries of the database in dir by ID.
+func loadDB(ctx context.Context, dir string) (map[string]*osv.Entry, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	u, err := web.URLFromFilePath(abs)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.NewClient(ctx, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	ids, err := c.ListIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	entries, err := c.ByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	db := make(map[string]*osv.Entry)
+	for _, e := range entries {
+		db[e.ID] = e
+	}
+	return db, nil
+}
+
+// A change describes how the entry with an ID differs
+// between two databases.
+type change struct {
+	id     string
+	kind   string // "added", "removed", "withdrawn", or "modified"
+	fields []fieldDiff
+}
+
+// A fieldDiff is a top-level field of an OSV entry with different
+// values in two databases. The values are in JSON, and empty if the
+// field is not set.
+type fieldDiff struct {
+	name     string
+	old, new string
+}
+
+// diff returns the changes from the entries in before to
+// those in after, sorted by ID.
+func diff(before, after map[string]*osv.Entry) []change {
+	var ids []string
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var changes []change
+	for _, id := range ids {
+		o, n := before[id], after[id]
+		switch {
+		case o == nil:
+			changes = append(changes, change{id: id, kind: "added"})
+		case n == nil:
+			changes = append(changes, change{id: id, kind: "removed"})
+		default:
+			fields := fieldDiffs(o, n)
+			if len(fields) == 0 {
+				continue
+			}
+			kind := "modified"
+			if o.Withdrawn == nil && n.Withdrawn != nil {
+				kind = "withdrawn"
+			}
+			changes = append(changes, change{id: id, kind: kind, fields: fields})
+		}
+	}
+	return changes
+}
+
+// fieldDiffs returns the top-level fields of o and n that differ,
+// in the order of their declaration in osv.Entry.
+func fieldDiffs(o, n *osv.Entry) []fieldDiff {
+	var diffs []fieldDiff
+	ov, nv := reflect.ValueOf(o).Elem(), reflect.ValueOf(n).Elem()
+	for i := 0; i < ov.NumField(); i++ {
+		name, _, _ := strings.Cut(ov.Type().Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		of, nf := jsonValue(ov.Field(i)), jsonValue(nv.Field(i))
+		if of != nf {
+			diffs = append(diffs, fieldDiff{name: name, old: of, new: nf})
+		}
+	}
+	return diffs
+}
+
+// jsonValue returns the JSON encoding of v, or "" if v is the
+// zero value, which the omitempty fields of osv.Entry omit.
+func jsonValue(v reflect.Value) string {
+	if v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0) {
+		return ""
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(b)
+}
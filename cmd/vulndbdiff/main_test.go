@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestRun(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(id string, modified time.Time, fixed string) *osv.Entry {
+		return &osv.Entry{
+			ID:       id,
+			Modified: modified,
+			Affected: []osv.Affected{{
+				Module: osv.Module{Path: "example.com/m", Ecosystem: osv.GoEcosystem},
+				Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: fixed}}}},
+			}},
+		}
+	}
+	withdrawn := entry("GO-2024-0003", t2, "1.0.0")
+	withdrawn.Withdrawn = &t2
+
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	if err := client.WriteDB(oldDir, []*osv.Entry{
+		entry("GO-2024-0001", t1, "1.0.0"),
+		entry("GO-2024-0002", t1, "1.0.0"),
+		entry("GO-2024-0003", t1, "1.0.0"),
+		entry("GO-2024-0004", t1, "1.0.0"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.WriteDB(newDir, []*osv.Entry{
+		entry("GO-2024-0001", t1, "1.0.0"),
+		entry("GO-2024-0002", t2, "1.0.1"),
+		withdrawn,
+		entry("GO-2024-0005", t2, "1.0.0"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got strings.Builder
+	if err := run(context.Background(), &got, oldDir, newDir); err != nil {
+		t.Fatal(err)
+	}
+	want := `modified GO-2024-0002
+  modified:
+    - "2024-01-01T00:00:00Z"
+    + "2024-02-01T00:00:00Z"
+  affected:
+    - [{"package":{"name":"example.com/m","ecosystem":"Go"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.0.0"}]}],"ecosystem_specific":{}}]
+    + [{"package":{"name":"example.com/m","ecosystem":"Go"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.0.1"}]}],"ecosystem_specific":{}}]
+withdrawn GO-2024-0003
+  modified:
+    - "2024-01-01T00:00:00Z"
+    + "2024-02-01T00:00:00Z"
+  withdrawn:
+    + "2024-02-01T00:00:00Z"
+removed GO-2024-0004
+added GO-2024-0005
+`
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("run() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -7,11 +7,10 @@ require (
 	github.com/google/go-cmp v0.6.0
 	golang.org/x/mod v0.22.0
 	golang.org/x/sync v0.10.0
+	golang.org/x/sys v0.29.0
 	golang.org/x/telemetry v0.0.0-20240522233618-39ace7a40ae7
+	golang.org/x/time v0.9.0
 	golang.org/x/tools v0.29.0
 )
 
-require (
-	github.com/google/renameio v0.1.0 // indirect
-	golang.org/x/sys v0.29.0 // indirect
-)
+require github.com/google/renameio v0.1.0 // indirect
@@ -18,11 +18,13 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 	"golang.org/x/vuln/internal/derrors"
 	"golang.org/x/vuln/internal/osv"
 	isem "golang.org/x/vuln/internal/semver"
@@ -32,10 +34,31 @@ import (
 // A Client for reading vulnerability databases.
 type Client struct {
 	source
+
+	// strictOSV makes decoding OSV entries fail on unknown fields.
+	strictOSV bool
+	// keepRawOSV records the JSON of OSV entries in their Raw field.
+	keepRawOSV bool
 }
 
 type Options struct {
 	HTTPClient *http.Client
+	// UserAgent is the value of the User-Agent header sent with
+	// requests to HTTP sources. If empty, the default User-Agent
+	// of HTTPClient is used.
+	UserAgent string
+	// Limiter, if set, throttles requests to HTTP sources. It is
+	// waited on before each request, so a limiter shared by several
+	// clients bounds the request rate of all of them together.
+	Limiter *rate.Limiter
+	// StrictOSV makes the client reject OSV entries containing
+	// fields it does not know about, instead of ignoring them.
+	// This is meant for checking that a database conforms to
+	// the OSV schema understood by govulncheck.
+	StrictOSV bool
+	// KeepRawOSV makes the client record the JSON of each OSV entry,
+	// as read from the database, in the Raw field of the entry.
+	KeepRawOSV bool
 }
 
 // NewClient returns a client that reads the vulnerability database
@@ -43,45 +66,59 @@ type Options struct {
 //
 // It supports databases following the API described
 // in https://go.dev/security/vuln/database#api.
-func NewClient(source string, opts *Options) (_ *Client, err error) {
+//
+// The schema of HTTP sources is checked with a request,
+// which is cancelled with ctx.
+func NewClient(ctx context.Context, source string, opts *Options) (_ *Client, err error) {
 	source = strings.TrimRight(source, "/")
 	uri, err := url.Parse(source)
 	if err != nil {
 		return nil, err
 	}
+	var c *Client
 	switch uri.Scheme {
 	case "http", "https":
-		return newHTTPClient(uri, opts)
+		c, err = newHTTPClient(ctx, uri, opts)
 	case "file":
-		return newLocalClient(uri)
+		c, err = newLocalClient(uri)
 	default:
 		return nil, fmt.Errorf("source %q has unsupported scheme", uri)
 	}
+	if err != nil {
+		return nil, err
+	}
+	c.strictOSV = opts != nil && opts.StrictOSV
+	c.keepRawOSV = opts != nil && opts.KeepRawOSV
+	return c, nil
 }
 
 var errUnknownSchema = errors.New("unrecognized vulndb format; see https://go.dev/security/vuln/database#api for accepted schema")
 
-func newHTTPClient(uri *url.URL, opts *Options) (*Client, error) {
+func newHTTPClient(ctx context.Context, uri *url.URL, opts *Options) (*Client, error) {
 	source := uri.String()
+	hs := newHTTPSource(source, opts)
 
-	// v1 returns true if the source likely follows the V1 schema.
-	v1 := func() bool {
-		return source == "https://vuln.go.dev" ||
-			endpointExistsHTTP(source, "index/modules.json.gz")
+	// v1 reports whether the source likely follows the V1 schema.
+	v1 := func() (bool, error) {
+		if source == "https://vuln.go.dev" {
+			return true, nil
+		}
+		return hs.exists(ctx, modulesEndpoint)
 	}
 
-	if v1() {
-		return &Client{source: newHTTPSource(uri.String(), opts)}, nil
+	ok, err := v1()
+	if err != nil {
+		// Do not report an unreachable database as
+		// one that does not follow the schema.
+		return nil, fmt.Errorf("vulnerability database %s could not be reached: %w", source, err)
+	}
+	if ok {
+		return &Client{source: hs}, nil
 	}
 
 	return nil, errUnknownSchema
 }
 
-func endpointExistsHTTP(source, endpoint string) bool {
-	r, err := http.Head(source + "/" + endpoint)
-	return err == nil && r.StatusCode == http.StatusOK
-}
-
 func newLocalClient(uri *url.URL) (*Client, error) {
 	dir, err := toDir(uri)
 	if err != nil {
@@ -162,6 +199,10 @@ type ModuleResponse struct {
 	Path    string
 	Version string
 	Entries []*osv.Entry
+	// SkippedNonGo is the number of entries of the module that
+	// were skipped because none of their affected modules are in
+	// the Go ecosystem.
+	SkippedNonGo int
 }
 
 // ByModules returns a list of responses
@@ -184,14 +225,15 @@ func (c *Client) ByModules(ctx context.Context, reqs []*ModuleRequest) (_ []*Mod
 	for i, req := range reqs {
 		i, req := i, req
 		g.Go(func() error {
-			entries, err := c.byModule(gctx, req, metas[i])
+			entries, skipped, err := c.byModule(gctx, req, metas[i])
 			if err != nil {
 				return err
 			}
 			resps[i] = &ModuleResponse{
-				Path:    req.Path,
-				Version: req.Version,
-				Entries: entries,
+				Path:         req.Path,
+				Version:      req.Version,
+				Entries:      entries,
+				SkippedNonGo: skipped,
 			}
 			return nil
 		})
@@ -232,19 +274,20 @@ func (c *Client) moduleMetas(ctx context.Context, reqs []*ModuleRequest) (_ []*m
 }
 
 // byModule returns the OSV entries matching the ModuleRequest,
-// or (nil, nil) if there are none.
-func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta) (_ []*osv.Entry, err error) {
+// or no entries if there are none, and the number of entries
+// skipped for not being in the Go ecosystem.
+func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta) (_ []*osv.Entry, skipped int, err error) {
 	// This module isn't in the database.
 	if m == nil {
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	if req.Path == "" {
-		return nil, fmt.Errorf("module path must be set")
+		return nil, 0, fmt.Errorf("module path must be set")
 	}
 
 	if req.Version != "" && !isem.Valid(req.Version) {
-		return nil, fmt.Errorf("version %s is not valid semver", req.Version)
+		return nil, 0, fmt.Errorf("version %s is not valid semver", req.Version)
 	}
 
 	var ids []string
@@ -255,12 +298,19 @@ func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta
 	}
 
 	if len(ids) == 0 {
-		return nil, nil
+		return nil, 0, nil
 	}
 
-	entries, err := c.byIDs(ctx, ids)
+	entries, err := c.ByIDs(ctx, ids)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	// Filter out entries of other ecosystems, which a
+	// misconfigured database may mix in with Go entries.
+	entries, skipped = goEntries(entries)
+	if len(entries) == 0 {
+		return nil, skipped, nil
 	}
 
 	// Filter by version.
@@ -281,7 +331,7 @@ func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta
 			}
 		}
 		if len(filtered) == 0 {
-			return nil, nil
+			return nil, skipped, nil
 		}
 	}
 
@@ -289,10 +339,107 @@ func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta
 		return entries[i].ID < entries[j].ID
 	})
 
-	return entries, nil
+	return entries, skipped, nil
+}
+
+// goEntries returns entries restricted to their affected modules
+// of the Go ecosystem, and the number of entries dropped for
+// affecting no such module.
+func goEntries(entries []*osv.Entry) (_ []*osv.Entry, skipped int) {
+	var goes []*osv.Entry
+	for _, e := range entries {
+		var affected []osv.Affected
+		for _, a := range e.Affected {
+			if isGo(a) {
+				affected = append(affected, a)
+			}
+		}
+		switch {
+		case len(affected) == 0:
+			skipped++
+		case len(affected) < len(e.Affected):
+			ge := *e
+			ge.Affected = affected
+			goes = append(goes, &ge)
+		default:
+			goes = append(goes, e)
+		}
+	}
+	return goes, skipped
+}
+
+// isGo reports whether a describes a module of the Go ecosystem.
+// An empty ecosystem is taken to be Go, as are modules with
+// Go-specific package data, whatever their stated ecosystem.
+func isGo(a osv.Affected) bool {
+	switch a.Module.Ecosystem {
+	case osv.GoEcosystem, "":
+		return true
+	}
+	return len(a.EcosystemSpecific.Packages) > 0
+}
+
+// ByAlias returns the OSV entries whose ID is id, or which have
+// id as an alias, such as a CVE or GHSA ID. It returns no entries,
+// and no error, if the database does not know id.
+func (c *Client) ByAlias(ctx context.Context, id string) (_ []*osv.Entry, err error) {
+	defer derrors.Wrap(&err, "ByAlias(%s)", id)
+
+	b, err := c.source.get(ctx, vulnsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := newStreamDecoder(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for dec.More() {
+		var v vulnMeta
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if v.ID == id || slices.Contains(v.Aliases, id) {
+			ids = append(ids, v.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return c.ByIDs(ctx, ids)
 }
 
-func (c *Client) byIDs(ctx context.Context, ids []string) (_ []*osv.Entry, err error) {
+// ListIDs returns the IDs of all the entries in the database, sorted.
+func (c *Client) ListIDs(ctx context.Context) (_ []string, err error) {
+	defer derrors.Wrap(&err, "ListIDs()")
+
+	b, err := c.source.get(ctx, vulnsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := newStreamDecoder(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for dec.More() {
+		var v vulnMeta
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		ids = append(ids, v.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// ByIDs returns the OSV entries with the given IDs, in the same
+// order. It returns an error if one of them does not exist.
+func (c *Client) ByIDs(ctx context.Context, ids []string) (_ []*osv.Entry, err error) {
 	entries := make([]*osv.Entry, len(ids))
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(10)
@@ -317,7 +464,7 @@ func (c *Client) byIDs(ctx context.Context, ids []string) (_ []*osv.Entry, err e
 // byID returns the OSV entry with the given ID,
 // or an error if it does not exist / cannot be unmarshaled.
 func (c *Client) byID(ctx context.Context, id string) (_ *osv.Entry, err error) {
-	derrors.Wrap(&err, "byID(%s)", id)
+	defer derrors.Wrap(&err, "byID(%s)", id)
 
 	b, err := c.source.get(ctx, entryEndpoint(id))
 	if err != nil {
@@ -325,9 +472,16 @@ func (c *Client) byID(ctx context.Context, id string) (_ *osv.Entry, err error)
 	}
 
 	var entry osv.Entry
-	if err := json.Unmarshal(b, &entry); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if c.strictOSV {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&entry); err != nil {
 		return nil, err
 	}
+	if c.keepRawOSV {
+		entry.Raw = b
+	}
 
 	return &entry, nil
 }
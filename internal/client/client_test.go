@@ -5,6 +5,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -13,10 +14,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
 	"golang.org/x/vuln/internal/osv"
 	"golang.org/x/vuln/internal/web"
 )
@@ -49,6 +52,11 @@ func newTestServer(dir string) *httptest.Server {
 	return httptest.NewServer(mux)
 }
 
+// roundTripFunc is an http.RoundTripper calling itself.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
 func entries(ids []string) ([]*osv.Entry, error) {
 	if len(ids) == 0 {
 		return nil, nil
@@ -83,7 +91,7 @@ func localURL(dir string) string {
 func TestNewClient(t *testing.T) {
 	t.Run("vuln.go.dev", func(t *testing.T) {
 		src := "https://vuln.go.dev"
-		c, err := NewClient(src, nil)
+		c, err := NewClient(context.Background(), src, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -96,7 +104,7 @@ func TestNewClient(t *testing.T) {
 		srv := newTestServer(testVulndb)
 		t.Cleanup(srv.Close)
 
-		c, err := NewClient(srv.URL, &Options{HTTPClient: srv.Client()})
+		c, err := NewClient(context.Background(), srv.URL, &Options{HTTPClient: srv.Client()})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -109,15 +117,61 @@ func TestNewClient(t *testing.T) {
 		srv := newTestServer(testLegacyVulndb)
 		t.Cleanup(srv.Close)
 
-		_, err := NewClient(srv.URL, &Options{HTTPClient: srv.Client()})
+		_, err := NewClient(context.Background(), srv.URL, &Options{HTTPClient: srv.Client()})
 		if err == nil || !errors.Is(err, errUnknownSchema) {
 			t.Errorf("NewClient() = %s, want error %s", err, errUnknownSchema)
 		}
 	})
 
+	t.Run("http/unreachable", func(t *testing.T) {
+		srv := newTestServer(testVulndb)
+		srv.Close()
+
+		_, err := NewClient(context.Background(), srv.URL, &Options{HTTPClient: srv.Client()})
+		if err == nil || errors.Is(err, errUnknownSchema) {
+			t.Errorf("NewClient() = %v, want error reaching the database", err)
+		}
+	})
+
+	t.Run("http/options", func(t *testing.T) {
+		srv := newTestServer(testVulndb)
+		t.Cleanup(srv.Close)
+
+		// The schema check goes through the configured
+		// HTTP client, with the configured User-Agent.
+		const want = "govulncheck/v1.2.3"
+		var agents []string
+		hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			agents = append(agents, r.Header.Get("User-Agent"))
+			return srv.Client().Transport.RoundTrip(r)
+		})}
+		if _, err := NewClient(context.Background(), srv.URL, &Options{HTTPClient: hc, UserAgent: want}); err != nil {
+			t.Fatal(err)
+		}
+		if len(agents) != 1 || agents[0] != want {
+			t.Errorf("got User-Agents %q, want one %q", agents, want)
+		}
+	})
+
+	t.Run("http/cancelled", func(t *testing.T) {
+		srv := newTestServer(testVulndb)
+		t.Cleanup(srv.Close)
+
+		// The limiter blocks, so the schema check
+		// only ends with the context.
+		limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+		limiter.Allow()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := NewClient(ctx, srv.URL, &Options{HTTPClient: srv.Client(), Limiter: limiter})
+		if err == nil {
+			t.Errorf("NewClient() = nil error, want rate limit error")
+		}
+	})
+
 	t.Run("local/v1", func(t *testing.T) {
 		src := testVulndbFileURL
-		c, err := NewClient(src, nil)
+		c, err := NewClient(context.Background(), src, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -128,7 +182,7 @@ func TestNewClient(t *testing.T) {
 
 	t.Run("local/flat", func(t *testing.T) {
 		src := testFlatVulndbFileURL
-		c, err := NewClient(src, nil)
+		c, err := NewClient(context.Background(), src, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -139,7 +193,7 @@ func TestNewClient(t *testing.T) {
 
 	t.Run("local/legacy", func(t *testing.T) {
 		src := testLegacyVulndbFileURL
-		_, err := NewClient(src, nil)
+		_, err := NewClient(context.Background(), src, nil)
 		if err == nil || !errors.Is(err, errUnknownSchema) {
 			t.Errorf("NewClient() = %s, want error %s", err, errUnknownSchema)
 		}
@@ -294,13 +348,55 @@ func TestByModules(t *testing.T) {
 	})
 }
 
+func TestByAlias(t *testing.T) {
+	tcs := []struct {
+		id      string
+		wantIDs []string
+	}{
+		{
+			id:      "GO-2022-0463",
+			wantIDs: []string{"GO-2022-0463"},
+		},
+		{
+			id:      "CVE-2015-5740",
+			wantIDs: []string{"GO-2021-0159"},
+		},
+		{
+			id:      "GHSA-95f9-94vc-665h",
+			wantIDs: []string{"GO-2022-0569"},
+		},
+		{
+			id:      "CVE-0000-0000",
+			wantIDs: nil,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.id, func(t *testing.T) {
+			test := func(t *testing.T, c *Client) {
+				got, err := c.ByAlias(context.Background(), tc.id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := entries(tc.wantIDs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("ByAlias(%s) mismatch (-want +got):\n%s", tc.id, diff)
+				}
+			}
+			testAllClientTypes(t, test)
+		})
+	}
+}
+
 // testAllClientTypes runs a given test for all client types.
 func testAllClientTypes(t *testing.T, test func(t *testing.T, c *Client)) {
 	t.Run("http", func(t *testing.T) {
 		srv := newTestServer(testVulndb)
 		t.Cleanup(srv.Close)
 
-		hc, err := NewClient(srv.URL, &Options{HTTPClient: srv.Client()})
+		hc, err := NewClient(context.Background(), srv.URL, &Options{HTTPClient: srv.Client()})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -309,7 +405,7 @@ func testAllClientTypes(t *testing.T, test func(t *testing.T, c *Client)) {
 	})
 
 	t.Run("local", func(t *testing.T) {
-		fc, err := NewClient(testVulndbFileURL, nil)
+		fc, err := NewClient(context.Background(), testVulndbFileURL, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -318,7 +414,7 @@ func testAllClientTypes(t *testing.T, test func(t *testing.T, c *Client)) {
 	})
 
 	t.Run("hybrid", func(t *testing.T) {
-		fc, err := NewClient(testFlatVulndbFileURL, nil)
+		fc, err := NewClient(context.Background(), testFlatVulndbFileURL, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -339,3 +435,143 @@ func testAllClientTypes(t *testing.T, test func(t *testing.T, c *Client)) {
 		test(t, mc)
 	})
 }
+
+func TestStrictOSV(t *testing.T) {
+	ctx := context.Background()
+	req := []*ModuleRequest{{Path: "github.com/beego/beego"}}
+
+	// The test database conforms to the schema.
+	c, err := NewClient(context.Background(), testVulndbFileURL, &Options{StrictOSV: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ByModules(ctx, req); err != nil {
+		t.Errorf("ByModules() = %v, want no error", err)
+	}
+
+	// Copy the database, adding an unknown field to an entry.
+	dir := t.TempDir()
+	index := filepath.Join(testVulndb, "index", "modules.json")
+	b, err := os.ReadFile(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "index"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index", "modules.json"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "ID"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"GO-2022-0463", "GO-2022-0569", "GO-2022-0572"} {
+		b, err := os.ReadFile(filepath.Join(testVulndb, "ID", id+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id == "GO-2022-0569" {
+			b = append([]byte(`{"unknown_field":true,`), b[1:]...)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "ID", id+".json"), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lenient, err := NewClient(context.Background(), localURL(dir), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lenient.ByModules(ctx, req); err != nil {
+		t.Errorf("lenient ByModules() = %v, want no error", err)
+	}
+	strict, err := NewClient(context.Background(), localURL(dir), &Options{StrictOSV: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.ByModules(ctx, req); err == nil {
+		t.Error("strict ByModules() = nil, want error on unknown field")
+	}
+
+	// The raw entries keep the unknown field.
+	raw, err := NewClient(context.Background(), localURL(dir), &Options{KeepRawOSV: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := raw.ByModules(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range res {
+		for _, e := range r.Entries {
+			if got, want := bytes.Contains(e.Raw, []byte("unknown_field")), e.ID == "GO-2022-0569"; got != want {
+				t.Errorf("raw JSON of %s has unknown field: %t, want %t", e.ID, got, want)
+			}
+		}
+	}
+}
+
+func TestGoEntries(t *testing.T) {
+	goMod := osv.Affected{Module: osv.Module{Path: "example.com/m", Ecosystem: osv.GoEcosystem}}
+	unset := osv.Affected{Module: osv.Module{Path: "example.com/u"}}
+	npm := osv.Affected{Module: osv.Module{Path: "left-pad", Ecosystem: "npm"}}
+	pypi := osv.Affected{Module: osv.Module{Path: "requests", Ecosystem: "PyPI"}}
+	// Go package data marks a module as Go whatever its ecosystem.
+	imports := osv.Affected{
+		Module:            osv.Module{Path: "example.com/i", Ecosystem: "npm"},
+		EcosystemSpecific: osv.EcosystemSpecific{Packages: []osv.Package{{Path: "example.com/i"}}},
+	}
+
+	got, skipped := goEntries([]*osv.Entry{
+		{ID: "GO", Affected: []osv.Affected{goMod}},
+		{ID: "UNSET", Affected: []osv.Affected{unset}},
+		{ID: "MIXED", Affected: []osv.Affected{npm, goMod}},
+		{ID: "NPM", Affected: []osv.Affected{npm}},
+		{ID: "PYPI", Affected: []osv.Affected{pypi, npm}},
+		{ID: "IMPORTS", Affected: []osv.Affected{imports}},
+	})
+	want := []*osv.Entry{
+		{ID: "GO", Affected: []osv.Affected{goMod}},
+		{ID: "UNSET", Affected: []osv.Affected{unset}},
+		{ID: "MIXED", Affected: []osv.Affected{goMod}},
+		{ID: "IMPORTS", Affected: []osv.Affected{imports}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("goEntries() mismatch (-want, +got):\n%s", diff)
+	}
+	if skipped != 2 {
+		t.Errorf("goEntries() skipped %d entries, want 2", skipped)
+	}
+}
+
+func TestListIDs(t *testing.T) {
+	want := slices.Clone(testIDs)
+	slices.Sort(want)
+	testAllClientTypes(t, func(t *testing.T, c *Client) {
+		got, err := c.ListIDs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ListIDs() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestByModulesSkippedNonGo(t *testing.T) {
+	npm := &osv.Entry{ID: "NPM", Affected: []osv.Affected{{Module: osv.Module{Path: "example.com/m", Ecosystem: "npm"}}}}
+	c, err := NewInMemoryClient([]*osv.Entry{npm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The count is per call, even on a shared client.
+	for i := 0; i < 2; i++ {
+		resps, err := c.ByModules(context.Background(), []*ModuleRequest{{Path: "example.com/m"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resps[0].SkippedNonGo; got != 1 {
+			t.Errorf("call %d: SkippedNonGo = %d, want 1", i, got)
+		}
+	}
+}
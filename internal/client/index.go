@@ -69,12 +69,14 @@ func indexFromEntries(entries []*osv.Entry) (map[string][]byte, error) {
 type index struct {
 	db      *dbMeta
 	modules modulesIndex
+	vulns   vulnsIndex
 }
 
 func newIndex() *index {
 	return &index{
 		db:      &dbMeta{},
 		modules: make(map[string]*moduleMeta),
+		vulns:   vulnsIndex{},
 	}
 }
 
@@ -83,6 +85,12 @@ func (i *index) add(entry *osv.Entry) {
 	if entry.Modified.After(i.db.Modified) {
 		i.db.Modified = entry.Modified
 	}
+	// Add to vulns index.
+	i.vulns = append(i.vulns, &vulnMeta{
+		ID:       entry.ID,
+		Modified: entry.Modified,
+		Aliases:  entry.Aliases,
+	})
 	// Add to modules index.
 	for _, affected := range entry.Affected {
 		modulePath := affected.Module.Path
@@ -116,5 +124,11 @@ func (i *index) raw() (map[string][]byte, error) {
 	}
 	data[modulesEndpoint] = b
 
+	b, err = json.Marshal(i.vulns)
+	if err != nil {
+		return nil, err
+	}
+	data[vulnsEndpoint] = b
+
 	return data, nil
 }
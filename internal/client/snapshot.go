@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/vuln/internal/osv"
+)
+
+// WriteDB writes a database holding entries to dir, following the
+// API described in https://go.dev/security/vuln/database#api, so that
+// a client for the "file" URL of dir reads exactly these entries.
+func WriteDB(dir string, entries []*osv.Entry) error {
+	src, err := newInMemorySource(entries)
+	if err != nil {
+		return err
+	}
+	for endpoint, b := range src.data {
+		path := filepath.Join(dir, filepath.FromSlash(endpoint)+".json")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestWriteDB(t *testing.T) {
+	ctx := context.Background()
+	src, err := NewClient(context.Background(), testVulndbFileURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := []*ModuleRequest{
+		{Path: "github.com/beego/beego", Version: "1.12.10"},
+		{Path: "golang.org/x/crypto"},
+	}
+	want, err := src.ByModules(ctx, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	var consulted []*osv.Entry
+	for _, resp := range want {
+		consulted = append(consulted, resp.Entries...)
+	}
+	if len(consulted) == 0 {
+		t.Fatal("no entries found in the test database")
+	}
+	if err := WriteDB(dir, consulted); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := NewClient(context.Background(), localURL(dir), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := snapshot.ByModules(ctx, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ByModules() mismatch (-want, +got):\n%s", diff)
+	}
+}
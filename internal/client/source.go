@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"golang.org/x/time/rate"
 	"golang.org/x/vuln/internal/derrors"
 	"golang.org/x/vuln/internal/osv"
 )
@@ -30,16 +31,24 @@ type source interface {
 
 func newHTTPSource(url string, opts *Options) *httpSource {
 	c := http.DefaultClient
-	if opts != nil && opts.HTTPClient != nil {
-		c = opts.HTTPClient
+	var userAgent string
+	var limiter *rate.Limiter
+	if opts != nil {
+		if opts.HTTPClient != nil {
+			c = opts.HTTPClient
+		}
+		userAgent = opts.UserAgent
+		limiter = opts.Limiter
 	}
-	return &httpSource{url: url, c: c}
+	return &httpSource{url: url, c: c, userAgent: userAgent, limiter: limiter}
 }
 
 // httpSource reads a vulnerability database from an http(s) source.
 type httpSource struct {
-	url string
-	c   *http.Client
+	url       string
+	c         *http.Client
+	userAgent string
+	limiter   *rate.Limiter // nil if requests are not throttled
 }
 
 func (hs *httpSource) get(ctx context.Context, endpoint string) (_ []byte, err error) {
@@ -47,11 +56,7 @@ func (hs *httpSource) get(ctx context.Context, endpoint string) (_ []byte, err e
 
 	method := http.MethodGet
 	reqURL := fmt.Sprintf("%s/%s", hs.url, endpoint+".json.gz")
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := hs.c.Do(req)
+	resp, err := hs.do(ctx, method, reqURL)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +75,35 @@ func (hs *httpSource) get(ctx context.Context, endpoint string) (_ []byte, err e
 	return io.ReadAll(r)
 }
 
+// exists reports whether endpoint, which should be bare as for get,
+// exists in hs. It returns an error if hs could not be reached.
+func (hs *httpSource) exists(ctx context.Context, endpoint string) (bool, error) {
+	resp, err := hs.do(ctx, http.MethodHead, fmt.Sprintf("%s/%s", hs.url, endpoint+".json.gz"))
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// do sends a request with method for reqURL,
+// with the User-Agent and throttling of hs.
+func (hs *httpSource) do(ctx context.Context, method, reqURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if hs.userAgent != "" {
+		req.Header.Set("User-Agent", hs.userAgent)
+	}
+	if hs.limiter != nil {
+		if err := hs.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return hs.c.Do(req)
+}
+
 func newLocalSource(dir string) *localSource {
 	return &localSource{fs: os.DirFS(dir)}
 }
@@ -6,8 +6,13 @@ package client
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 func TestGet(t *testing.T) {
@@ -42,6 +47,49 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestHTTPSourceUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	const want = "govulncheck/v1.2.3"
+	hs := newHTTPSource(srv.URL, &Options{HTTPClient: srv.Client(), UserAgent: want})
+	if _, err := hs.get(context.Background(), "index/db"); err == nil {
+		t.Fatal("get: got nil error, want not found")
+	}
+	if got != want {
+		t.Errorf("User-Agent = %q, want %q", got, want)
+	}
+}
+
+func TestHTTPSourceLimiter(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	// Allow a single request, then block for much
+	// longer than the deadline of the second one.
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	hs := newHTTPSource(srv.URL, &Options{HTTPClient: srv.Client(), Limiter: limiter})
+	if _, err := hs.get(context.Background(), "index/db"); err == nil {
+		t.Fatal("get: got nil error, want not found")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := hs.get(ctx, "index/db"); err == nil {
+		t.Fatal("get: got nil error, want rate limit error")
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1", requests)
+	}
+}
+
 // testAllSourceTypes runs a given test for all source types.
 func testAllSourceTypes(t *testing.T, test func(t *testing.T, s source)) {
 	t.Run("http", func(t *testing.T) {
@@ -41,12 +41,15 @@ type Message struct {
 	Progress *Progress `json:"progress,omitempty"`
 	SBOM     *SBOM     `json:"SBOM,omitempty"`
 	// OSV is emitted for every vulnerability in the current database
-	// that applies to user modules regardless of their version. If a
-	// module is being used at a vulnerable version, the corresponding
-	// OSV will be referenced in Findings depending on the type of usage
-	// and the desired scan level.
+	// that applies to user modules regardless of their version or
+	// platform, so the OSV messages of a stream are the complete set
+	// of advisories consulted. If a module is being used at a
+	// vulnerable version, the corresponding OSV will be referenced in
+	// Findings depending on the type of usage and the desired scan level.
 	OSV     *osv.Entry `json:"osv,omitempty"`
 	Finding *Finding   `json:"finding,omitempty"`
+	Graph   *Graph     `json:"graph,omitempty"`
+	Timing  *Timing    `json:"timing,omitempty"`
 }
 
 // Config must occur as the first message of a stream and informs the client
@@ -84,6 +87,128 @@ type Config struct {
 	// what to do with it. Valid values are source, binary, query,
 	// and extract.
 	ScanMode ScanMode `json:"scan_mode,omitempty"`
+
+	// MainModules are the paths of the main modules of the code
+	// analyzed in source mode: the module the scan ran in, or the
+	// modules of its workspace. They identify the code a stream of
+	// findings is for.
+	MainModules []string `json:"main_modules,omitempty"`
+
+	// Patterns are the package patterns analyzed in source mode, as
+	// provided by the user. The packages they match are the roots of
+	// the SBOM.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Tags are the build tags used for loading packages in source mode.
+	Tags []string `json:"tags,omitempty"`
+
+	// Tools indicates that the packages analyzed in source mode are the
+	// build tools imported by the files constrained by the "tools" build
+	// tag, such as tools.go, among the packages matched by Patterns.
+	Tools bool `json:"tools,omitempty"`
+
+	// Test indicates that test files were analyzed in source mode.
+	Test bool `json:"test,omitempty"`
+
+	// GOOS and GOARCH are the platform packages were loaded for in
+	// source mode.
+	GOOS   string `json:"goos,omitempty"`
+	GOARCH string `json:"goarch,omitempty"`
+
+	// PackagesScanned is the number of packages analyzed, including
+	// the root packages and all of their dependencies. It is only set
+	// in source mode at package and symbol scan level.
+	PackagesScanned int `json:"packages_scanned,omitempty"`
+
+	// ModulesScanned is the number of modules, including the standard
+	// library, that the analyzed packages belong to. It is only set
+	// in source mode at package and symbol scan level.
+	ModulesScanned int `json:"modules_scanned,omitempty"`
+
+	// Overrides maps module paths to the lowest module version
+	// considered fixed locally, regardless of the vulnerability
+	// database. Modules at or above their override version are
+	// treated as not affected by any vulnerability.
+	Overrides map[string]string `json:"overrides,omitempty"`
+
+	// AssumedVersions maps module paths to the module version their
+	// vulnerabilities are matched against, instead of the version
+	// used by the scanned code. Findings still report the version
+	// used by the code.
+	AssumedVersions map[string]string `json:"assumed_versions,omitempty"`
+
+	// MinGoVersion is the oldest Go version, such as go1.19, the
+	// analyzed code is expected to be built with. If set, standard
+	// library vulnerabilities affecting any Go version from
+	// MinGoVersion to GoVersion are reported.
+	MinGoVersion string `json:"min_go_version,omitempty"`
+
+	// SkipModules lists the paths of modules excluded from the scan.
+	// No vulnerabilities are fetched or reported for these modules.
+	SkipModules []string `json:"skip_modules,omitempty"`
+
+	// EmitGraph indicates that the stream contains a Graph message
+	// describing how the root packages reach vulnerable packages.
+	// It is only supported in source mode at package and symbol
+	// scan level.
+	EmitGraph bool `json:"emit_graph,omitempty"`
+
+	// EmitOSV indicates that the OSV messages of the stream hold the
+	// entries exactly as read from the database, including any fields
+	// not described by osv.Entry.
+	EmitOSV bool `json:"emit_osv,omitempty"`
+
+	// Depth is "direct" if only the root packages and the packages
+	// they import directly were checked for vulnerabilities, instead
+	// of all their transitive dependencies. It is only supported in
+	// source mode at package and symbol scan level.
+	Depth string `json:"depth,omitempty"`
+
+	// EmitTimings indicates that the stream contains a Timing message
+	// for each phase of the scan as it completes. It is only supported
+	// in source and binary mode.
+	EmitTimings bool `json:"emit_timings,omitempty"`
+
+	// EmitEntryPoints indicates that symbol level findings list all
+	// the entry functions from which their vulnerable symbol is
+	// reachable in EntryPoints. It is only supported in source mode
+	// at symbol scan level.
+	EmitEntryPoints bool `json:"emit_entry_points,omitempty"`
+
+	// FailFast indicates that the analysis stops at the first called
+	// vulnerability, by OSV ID, so that only its package and symbol
+	// level findings are emitted even if more vulnerabilities are
+	// called. It is only supported in source mode at symbol scan level.
+	FailFast bool `json:"fail_fast,omitempty"`
+
+	// HideGenerated indicates that vulnerable symbols reachable only
+	// through functions declared in generated files, see
+	// Frame.Generated, are not reported at symbol level. It is only
+	// supported in source mode at symbol scan level.
+	HideGenerated bool `json:"hide_generated,omitempty"`
+
+	// AsLibrary indicates that the entry functions of the analysis
+	// are the exported functions and methods of the packages that
+	// other modules can import from the main modules, instead of
+	// those of all the root packages. Main packages and internal
+	// packages are not entry points, and nor are packages outside
+	// the main modules. It is only supported in source mode at
+	// symbol scan level.
+	AsLibrary bool `json:"as_library,omitempty"`
+
+	// Stream indicates that the calls of each vulnerability are
+	// analyzed separately, in order of OSV ID, and the symbol level
+	// findings of each called vulnerability are emitted as soon as its
+	// call stacks are found, instead of after the calls of all the
+	// vulnerabilities are analyzed. It is only supported in source mode
+	// at symbol scan level.
+	Stream bool `json:"stream,omitempty"`
+
+	// Stacks is the maximum number of call stacks, and hence of
+	// symbol level findings, reported for each called vulnerable
+	// symbol. Zero means one. It is only supported in source mode
+	// at symbol scan level.
+	Stacks int `json:"stacks,omitempty"`
 }
 
 // SBOM contains minimal information about the artifacts govulncheck is scanning.
@@ -107,6 +232,50 @@ type Module struct {
 
 	// The version of the module.
 	Version string `json:"version,omitempty"`
+
+	// Replaces is the path of the module that this module replaces
+	// in the build, if that path is different from Path.
+	Replaces string `json:"replaces,omitempty"`
+}
+
+// Graph is the slice of the package import graph and of the module
+// requires graph that leads from the root packages to the packages
+// with vulnerabilities.
+//
+// The module requires graph is derived from the package import graph:
+// a module requires another module if one of its packages imports a
+// package of the other module.
+type Graph struct {
+	// Packages are the package nodes of the graph.
+	Packages []*GraphNode `json:"packages,omitempty"`
+
+	// Modules are the module nodes of the graph.
+	Modules []*GraphNode `json:"modules,omitempty"`
+}
+
+// GraphNode is a package or a module in a Graph.
+type GraphNode struct {
+	// Path is the package or module path.
+	Path string `json:"path"`
+
+	// Version is the module version. It is only set for
+	// module nodes.
+	Version string `json:"version,omitempty"`
+
+	// Edges are the paths of the imported packages, for package
+	// nodes, or of the required modules, for module nodes.
+	Edges []string `json:"edges,omitempty"`
+}
+
+// Timing is the wall-clock time spent in a phase of the scan, such
+// as loading packages or building the call graph. Some phases run
+// concurrently, so their durations can add up to more than the total.
+type Timing struct {
+	// Phase is the name of the phase.
+	Phase string `json:"phase"`
+
+	// Duration is the time spent in the phase, in nanoseconds.
+	Duration time.Duration `json:"duration"`
 }
 
 // Progress messages are informational only, intended to allow users to monitor
@@ -119,6 +288,12 @@ type Progress struct {
 
 	// Message is the progress message.
 	Message string `json:"message,omitempty"`
+
+	// SymbolsFailed indicates that the call graph of the scanned code
+	// could not be built, so that the symbol level scan of the Config
+	// was lowered to the package level: the findings of the stream are
+	// those of a package level scan. Message then explains why.
+	SymbolsFailed bool `json:"symbols_failed,omitempty"`
 }
 
 // Finding contains information on a discovered vulnerability. Each vulnerability
@@ -129,10 +304,20 @@ type Progress struct {
 // Multiple symbol level findings can be emitted when multiple symbols of the
 // same vuln are called or govulncheck decides to show multiple traces for the
 // same symbol.
+// When a module is used at several versions, which can happen with module
+// replacements, findings are emitted for each version. They can be told
+// apart by the version of their vulnerable frame.
 type Finding struct {
 	// OSV is the id of the detected vulnerability.
 	OSV string `json:"osv,omitempty"`
 
+	// Level is the precision at which the vulnerability was found:
+	// symbol if Trace is a call stack reaching a vulnerable symbol,
+	// package if a vulnerable package is imported, and module if a
+	// vulnerable module is required. It is the same information
+	// that the first frame of Trace conveys.
+	Level ScanLevel `json:"level,omitempty"`
+
 	// FixedVersion is the module version where the vulnerability was
 	// fixed. This is empty if a fix is not available.
 	//
@@ -149,6 +334,31 @@ type Finding struct {
 	// fixed version.
 	FixedVersion string `json:"fixed_version,omitempty"`
 
+	// FixedVersions contains all the module versions in which the
+	// vulnerability was fixed, sorted increasingly. An OSV report
+	// with several disjoint affected ranges can have several fixes,
+	// and clients can use FixedVersions to pick the fix appropriate
+	// for the module version they use.
+	//
+	// As opposed to FixedVersion, FixedVersions is populated with
+	// every fix in the OSV report for the module.
+	FixedVersions []string `json:"fixed_versions,omitempty"`
+
+	// IntroducedVersion is the module version where the vulnerability
+	// was introduced, that is the start of the affected range of the
+	// OSV report containing the found module version. It is "v0.0.0"
+	// when the vulnerability is present from the first version of the
+	// module, and empty if it cannot be determined.
+	IntroducedVersion string `json:"introduced_version,omitempty"`
+
+	// MatchedVersion is the module version the vulnerability was
+	// matched against, if it is not the version found in the trace.
+	// For instance, with -min-go, a standard library vulnerability
+	// that only affects older Go versions than the one scanned is
+	// matched against the oldest affected one. FixedVersion and
+	// IntroducedVersion are then those of MatchedVersion.
+	MatchedVersion string `json:"matched_version,omitempty"`
+
 	// Trace contains an entry for each frame in the trace.
 	//
 	// Frames are sorted starting from the imported vulnerable symbol
@@ -163,6 +373,38 @@ type Finding struct {
 	// findings, the trace will contain a single-frame with no symbol or position
 	// information.
 	Trace []*Frame `json:"trace,omitempty"`
+
+	// ImportChain is the witness of package level source findings.
+	// It contains the import paths of the packages on a shortest
+	// chain of imports from a root package to the vulnerable package,
+	// ordered like Trace: starting with the vulnerable package and
+	// ending with the root package.
+	ImportChain []string `json:"import_chain,omitempty"`
+
+	// EntryPoints contains a frame for each entry function of the
+	// scanned code from which the vulnerable symbol of a symbol level
+	// finding is reachable, when requested by Config.EmitEntryPoints.
+	// The last frame of Trace is one of them. The position of a frame
+	// is the position of the function.
+	EntryPoints []*Frame `json:"entry_points,omitempty"`
+
+	// EntryPointCount is the number of entry functions of the scanned
+	// code from which the vulnerable symbol of a symbol level source
+	// finding is reachable, whether or not they are listed in
+	// EntryPoints. A symbol reachable from many entry functions is
+	// likely more entangled with the code than one reachable from a
+	// single one. Entry functions are counted instead of call paths,
+	// whose number can grow exponentially with the size of the code.
+	EntryPointCount int `json:"entry_point_count,omitempty"`
+
+	// Reachability tells how much is known about whether the
+	// vulnerable symbol of a symbol level finding can be called.
+	// It is empty in source mode, where Trace is a call stack that
+	// reaches the symbol, and ReachabilityPresent in binary mode,
+	// where only the presence of the symbol in the binary is known.
+	// The linker removes most unreachable code, but not all of it,
+	// so such findings can be false positives.
+	Reachability Reachability `json:"reachability,omitempty"`
 }
 
 // Frame represents an entry in a finding trace.
@@ -195,6 +437,11 @@ type Frame struct {
 	// the enclosing module and always use "/" for
 	// portability.
 	Position *Position `json:"position,omitempty"`
+
+	// Generated reports whether the function is declared in a file
+	// of the scanned code marked as generated, such as protobuf or
+	// gRPC code, by a "// Code generated ... DO NOT EDIT." comment.
+	Generated bool `json:"generated,omitempty"`
 }
 
 // Position represents arbitrary source position.
@@ -226,12 +473,27 @@ func (l ScanLevel) WantSymbols() bool { return l == ScanLevelSymbol }
 // to generate package-level findings.
 func (l ScanLevel) WantPackages() bool { return l == ScanLevelPackage || l == ScanLevelSymbol }
 
+// Reachability represents how much is known about whether the
+// vulnerable symbol of a finding can be called. Source mode, which
+// computes call stacks, may distinguish further tiers in the future.
+type Reachability string
+
+const (
+	// ReachabilityPresent means that the vulnerable symbol
+	// is present in the scanned code, which might not call it.
+	ReachabilityPresent = "present"
+)
+
 // ScanMode represents the mode in which a scan occurred. This can
 // be necessary to correctly to interpret findings. For instance,
 // a binary can be checked for vulnerabilities or the user just wants
 // to extract minimal data necessary for the vulnerability check.
 type ScanMode string
 
+// DepthDirect is the Config.Depth of scans limited
+// to the direct imports of the root packages.
+const DepthDirect = "direct"
+
 const (
 	ScanModeSource  = "source"
 	ScanModeBinary  = "binary"
@@ -5,8 +5,12 @@
 package govulncheck_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
 	"golang.org/x/vuln/internal/test"
 )
 
@@ -15,3 +19,28 @@ func TestImports(t *testing.T) {
 		"golang.org/x/vuln/internal/osv", // allowed to pull in the osv json entries
 	)
 }
+
+func TestRawOSV(t *testing.T) {
+	raw := []byte(`{"id":"GO-0000-0001","details":"","affected":null,"unknown_field":true}`)
+	var first bytes.Buffer
+	h := govulncheck.NewJSONHandler(&first)
+	if err := h.Config(&govulncheck.Config{EmitOSV: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.OSV(&osv.Entry{ID: "GO-0000-0001", Raw: raw}); err != nil {
+		t.Fatal(err)
+	}
+	want := first.String()
+	if !strings.Contains(want, `"unknown_field": true`) {
+		t.Fatalf("raw OSV entry not written:\n%s", want)
+	}
+
+	// Replaying the stream keeps the raw entries.
+	var second bytes.Buffer
+	if err := govulncheck.HandleJSON(&first, govulncheck.NewJSONHandler(&second)); err != nil {
+		t.Fatal(err)
+	}
+	if got := second.String(); got != want {
+		t.Errorf("replayed stream =\n%s\nwant\n%s", got, want)
+	}
+}
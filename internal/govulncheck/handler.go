@@ -24,25 +24,55 @@ type Handler interface {
 	Progress(progress *Progress) error
 
 	// OSV is invoked for each osv Entry in the stream.
+	//
+	// In source and binary mode, this is every entry fetched from the
+	// database for the modules analyzed, once, before any filtering by
+	// module version, platform, or override. Handlers can hence record
+	// the exact set of advisories a scan was based on.
 	OSV(entry *osv.Entry) error
 
 	// Finding is called for each vulnerability finding in the stream.
 	Finding(finding *Finding) error
+
+	// Graph is called with the import graph leading to vulnerable
+	// packages, if requested.
+	Graph(graph *Graph) error
+
+	// Timing is called when a phase of the scan completes,
+	// if timings are requested.
+	Timing(timing *Timing) error
 }
 
 // HandleJSON reads the json from the supplied stream and hands the decoded
-// output to the handler.
+// output to the handler. If the stream was produced with Config.EmitOSV,
+// the OSV entries handed over keep their raw JSON.
 func HandleJSON(from io.Reader, to Handler) error {
 	dec := json.NewDecoder(from)
+	keepRaw := false
 	for dec.More() {
-		msg := Message{}
+		msg := struct {
+			Message
+			// OSV shadows Message.OSV to keep the raw entry.
+			OSV json.RawMessage `json:"osv,omitempty"`
+		}{}
 		// decode the next message in the stream
 		if err := dec.Decode(&msg); err != nil {
 			return err
 		}
+		if msg.OSV != nil {
+			entry := &osv.Entry{}
+			if err := json.Unmarshal(msg.OSV, entry); err != nil {
+				return err
+			}
+			if keepRaw {
+				entry.Raw = msg.OSV
+			}
+			msg.Message.OSV = entry
+		}
 		// dispatch the message
 		var err error
 		if msg.Config != nil {
+			keepRaw = msg.Config.EmitOSV
 			err = to.Config(msg.Config)
 		}
 		if msg.Progress != nil {
@@ -51,12 +81,18 @@ func HandleJSON(from io.Reader, to Handler) error {
 		if msg.SBOM != nil {
 			err = to.SBOM(msg.SBOM)
 		}
-		if msg.OSV != nil {
-			err = to.OSV(msg.OSV)
+		if msg.Message.OSV != nil {
+			err = to.OSV(msg.Message.OSV)
 		}
 		if msg.Finding != nil {
 			err = to.Finding(msg.Finding)
 		}
+		if msg.Graph != nil {
+			err = to.Graph(msg.Graph)
+		}
+		if msg.Timing != nil {
+			err = to.Timing(msg.Timing)
+		}
 		if err != nil {
 			return err
 		}
@@ -39,7 +39,12 @@ func (h *jsonHandler) SBOM(sbom *SBOM) error {
 }
 
 // OSV writes an osv entry in JSON to the underlying writer.
+// If the entry has its raw JSON, that is written instead, so
+// that fields unknown to govulncheck are preserved.
 func (h *jsonHandler) OSV(entry *osv.Entry) error {
+	if len(entry.Raw) > 0 {
+		return h.enc.Encode(rawOSVMessage{OSV: entry.Raw})
+	}
 	return h.enc.Encode(Message{OSV: entry})
 }
 
@@ -47,3 +52,18 @@ func (h *jsonHandler) OSV(entry *osv.Entry) error {
 func (h *jsonHandler) Finding(finding *Finding) error {
 	return h.enc.Encode(Message{Finding: finding})
 }
+
+// Graph writes the import graph in JSON to the underlying writer.
+func (h *jsonHandler) Graph(graph *Graph) error {
+	return h.enc.Encode(Message{Graph: graph})
+}
+
+// Timing writes a phase timing in JSON to the underlying writer.
+func (h *jsonHandler) Timing(timing *Timing) error {
+	return h.enc.Encode(Message{Timing: timing})
+}
+
+// rawOSVMessage is a Message holding an OSV entry in its raw form.
+type rawOSVMessage struct {
+	OSV json.RawMessage `json:"osv"`
+}
@@ -51,7 +51,13 @@ func (h *handler) Config(cfg *govulncheck.Config) error {
 	return nil
 }
 
-func (h *handler) Progress(progress *govulncheck.Progress) error {
+func (h *handler) Progress(p *govulncheck.Progress) error {
+	if p.SymbolsFailed && h.cfg != nil {
+		// The findings are those of a package level scan.
+		lowered := *h.cfg
+		lowered.ScanLevel = govulncheck.ScanLevelPackage
+		h.cfg = &lowered
+	}
 	return nil
 }
 
@@ -65,6 +71,14 @@ func (h *handler) OSV(e *osv.Entry) error {
 	return nil
 }
 
+func (h *handler) Graph(g *govulncheck.Graph) error {
+	return nil // not needed by openvex
+}
+
+func (h *handler) Timing(t *govulncheck.Timing) error {
+	return nil // not needed by openvex
+}
+
 // foundAtLevel returns the level at which a specific finding is present in the
 // scanned product.
 func foundAtLevel(f *govulncheck.Finding) findingLevel {
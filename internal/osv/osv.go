@@ -14,20 +14,34 @@
 // range type is implemented).
 package osv
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // RangeType specifies the type of version range being recorded and
 // defines the interpretation of the RangeEvent object's Introduced
 // and Fixed fields.
 //
-// In this implementation, only the "SEMVER" type is supported.
+// In this implementation, the "SEMVER" type is supported, as well as
+// the "ECOSYSTEM" type when its versions are semantic versions, which
+// Go module versions are. The "GIT" type is only understood for
+// pseudo-versions of the commits that its events mention.
 //
 // See https://ossf.github.io/osv-schema/#affectedrangestype-field.
 type RangeType string
 
-// RangeTypeSemver indicates a semantic version as defined by
-// SemVer 2.0.0, with no leading "v" prefix.
-const RangeTypeSemver RangeType = "SEMVER"
+const (
+	// RangeTypeSemver indicates a semantic version as defined by
+	// SemVer 2.0.0, with no leading "v" prefix.
+	RangeTypeSemver RangeType = "SEMVER"
+	// RangeTypeGit indicates full-length git commit hashes.
+	RangeTypeGit RangeType = "GIT"
+	// RangeTypeEcosystem indicates versions of the ecosystem of the
+	// affected package, which are Go module versions in the Go
+	// ecosystem.
+	RangeTypeEcosystem RangeType = "ECOSYSTEM"
+)
 
 // Ecosystem identifies the overall library ecosystem.
 // In this implementation, only the "Go" ecosystem is supported.
@@ -85,7 +99,8 @@ type RangeEvent struct {
 type Range struct {
 	// Type is the version type that should be used to interpret the
 	// versions in Events. Required.
-	// In this implementation, only the "SEMVER" type is supported.
+	// In this implementation, "SEMVER" and, partially, "ECOSYSTEM"
+	// and "GIT" types are supported.
 	Type RangeType `json:"type"`
 	// Events is a list of versions representing the ranges in which
 	// the module is vulnerable. Required.
@@ -154,6 +169,12 @@ type Package struct {
 	// GOARCH specifies the execution architecture where the symbols appear, if
 	// known.
 	GOARCH []string `json:"goarch,omitempty"`
+	// Platforms lists the exact GOOS/GOARCH pairs, for example
+	// "linux/amd64", where the symbols appear, if known. GOOS and
+	// GOARCH lists apply as a cross product, so Platforms is used
+	// when only specific pairs are affected. If both are present,
+	// Platforms takes precedence.
+	Platforms []string `json:"platforms,omitempty"`
 	// Symbols is a list of function and method names affected by
 	// this vulnerability. Methods are listed as <recv>.<method>.
 	//
@@ -215,6 +236,10 @@ type Entry struct {
 	// DatabaseSpecific contains additional information about the
 	// vulnerability, specific to the Go vulnerability database.
 	DatabaseSpecific *DatabaseSpecific `json:"database_specific,omitempty"`
+
+	// Raw, if set, is the JSON encoding of the entry as read from the
+	// database, including any fields not described by this type.
+	Raw json.RawMessage `json:"-"`
 }
 
 // Credit represents a credit for the discovery, confirmation, patch, or
@@ -237,4 +262,7 @@ type DatabaseSpecific struct {
 	URL string `json:"url,omitempty"`
 	// The review status of this report (UNREVIEWED or REVIEWED).
 	ReviewStatus ReviewStatus `json:"review_status,omitempty"`
+	// The IDs of the weaknesses in the Common Weakness Enumeration
+	// that lead to this vulnerability, of the form "CWE-NNN".
+	CWEIDs []string `json:"cwe_ids,omitempty"`
 }
@@ -11,5 +11,7 @@ import (
 )
 
 func TestImports(t *testing.T) {
-	test.VerifyImports(t) // no non stdlib imports allowed
+	test.VerifyImports(t,
+		"golang.org/x/mod/semver", // used to validate the versions of entries
+	)
 }
@@ -0,0 +1,156 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package osv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// ParseEntry decodes an entry in the Go OSV format from data.
+// Unlike a plain json.Unmarshal, it fails on fields that the
+// format does not define, which usually are typos in hand-written
+// entries. It does not validate the entry, see Entry.Validate.
+func ParseEntry(data []byte) (*Entry, error) {
+	var e Entry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&e); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the entry")
+	}
+	return &e, nil
+}
+
+var (
+	cveRegexp  = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+	cweRegexp  = regexp.MustCompile(`^CWE-\d+$`)
+	ghsaRegexp = regexp.MustCompile(`^GHSA-[23456789cfghjmpqrvwx]{4}(-[23456789cfghjmpqrvwx]{4}){2}$`)
+)
+
+// Validate reports the problems of e, if any: missing required
+// fields, malformed version ranges and CWE IDs, and aliases that
+// are neither CVE nor GHSA IDs. All problems are reported, joined in a single
+// error.
+//
+// The versions of SEMVER ranges must be semantic versions without
+// a "v" prefix, and the events of a range must alternate between
+// introducing and fixing versions in increasing order, starting
+// with an introducing version. Only the structure of other ranges
+// is checked.
+func (e *Entry) Validate() error {
+	var errs []error
+	addf := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	if e.ID == "" {
+		addf("missing id")
+	}
+	if e.Modified.IsZero() {
+		addf("missing modified time")
+	}
+	for _, a := range e.Aliases {
+		if !cveRegexp.MatchString(a) && !ghsaRegexp.MatchString(a) {
+			addf("alias %q is not a CVE or GHSA ID", a)
+		}
+	}
+	if len(e.Affected) == 0 {
+		addf("missing affected modules")
+	}
+	for i, a := range e.Affected {
+		prefix := fmt.Sprintf("affected[%d]", i)
+		if a.Module.Path == "" {
+			addf("%s: missing module path", prefix)
+		}
+		if a.Module.Ecosystem != GoEcosystem {
+			addf("%s: ecosystem is %q, want %q", prefix, a.Module.Ecosystem, GoEcosystem)
+		}
+		for j, r := range a.Ranges {
+			if err := r.validate(); err != nil {
+				addf("%s: ranges[%d]: %v", prefix, j, err)
+			}
+		}
+		for j, p := range a.EcosystemSpecific.Packages {
+			if p.Path == "" {
+				addf("%s: imports[%d]: missing package path", prefix, j)
+			}
+		}
+	}
+	for i, r := range e.References {
+		if r.Type == "" {
+			addf("references[%d]: missing type", i)
+		}
+		if r.URL == "" {
+			addf("references[%d]: missing url", i)
+		}
+	}
+	if ds := e.DatabaseSpecific; ds != nil {
+		for _, id := range ds.CWEIDs {
+			if !cweRegexp.MatchString(id) {
+				addf("database_specific: %q is not a CWE ID", id)
+			}
+		}
+	}
+	for i, c := range e.Credits {
+		if c.Name == "" {
+			addf("credits[%d]: missing name", i)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate returns an error describing the first problem of r.
+func (r *Range) validate() error {
+	if r.Type == "" {
+		return errors.New("missing type")
+	}
+	if len(r.Events) == 0 {
+		return errors.New("missing events")
+	}
+	var prev string // version of the previous event
+	for i, ev := range r.Events {
+		if (ev.Introduced == "") == (ev.Fixed == "") {
+			return fmt.Errorf("event %d must have exactly one of introduced and fixed", i)
+		}
+		if i%2 == 0 && ev.Introduced == "" {
+			return fmt.Errorf("event %d fixes %s, which was not introduced", i, ev.Fixed)
+		}
+		if i%2 == 1 && ev.Fixed == "" {
+			return fmt.Errorf("event %d introduces %s before the previous version was fixed", i, ev.Introduced)
+		}
+		if r.Type != RangeTypeSemver {
+			continue
+		}
+		v := ev.Introduced + ev.Fixed
+		if v == "0" && i == 0 {
+			prev = v
+			continue
+		}
+		if !validSemver(v) {
+			return fmt.Errorf("event %d: %q is not a semantic version", i, v)
+		}
+		if prev != "" && prev != "0" && semver.Compare("v"+prev, "v"+v) >= 0 {
+			return fmt.Errorf("event %d: %s does not come after %s", i, v, prev)
+		}
+		prev = v
+	}
+	return nil
+}
+
+// validSemver reports whether v is a full semantic version
+// without a "v" prefix. The shorthands accepted by semver.IsValid,
+// such as v1.2, are not valid in OSV entries.
+func validSemver(v string) bool {
+	w := "v" + v
+	return semver.IsValid(w) && strings.HasPrefix(w, semver.Canonical(w))
+}
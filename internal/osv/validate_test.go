@@ -0,0 +1,97 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package osv
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEntry(t *testing.T) {
+	e, err := ParseEntry([]byte(`{"id":"GO-2024-0001","modified":"2024-01-02T00:00:00Z","details":"d","affected":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "GO-2024-0001" {
+		t.Errorf("ID = %q, want GO-2024-0001", e.ID)
+	}
+	for _, data := range []string{
+		`{"id":"GO-2024-0001","aliasses":["CVE-2024-1234"]}`,
+		`{"id":"GO-2024-0001"} {"id":"GO-2024-0002"}`,
+		`{"id":`,
+	} {
+		if _, err := ParseEntry([]byte(data)); err == nil {
+			t.Errorf("ParseEntry(%s) succeeded, want error", data)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Entry {
+		return &Entry{
+			ID:       "GO-2024-0001",
+			Modified: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Aliases:  []string{"CVE-2024-12345", "GHSA-xxxx-2345-cfgh"},
+			Affected: []Affected{{
+				Module: Module{Path: "example.com/m", Ecosystem: GoEcosystem},
+				Ranges: []Range{{
+					Type: RangeTypeSemver,
+					Events: []RangeEvent{
+						{Introduced: "0"}, {Fixed: "1.0.0-rc.1"},
+						{Introduced: "1.0.0"}, {Fixed: "1.0.1"},
+					},
+				}},
+				EcosystemSpecific: EcosystemSpecific{Packages: []Package{{Path: "example.com/m/p"}}},
+			}},
+			References:       []Reference{{Type: ReferenceTypeFix, URL: "https://example.com/fix"}},
+			DatabaseSpecific: &DatabaseSpecific{CWEIDs: []string{"CWE-79"}},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate() of a valid entry = %v", err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		modify func(*Entry)
+		want   string
+	}{
+		{"no id", func(e *Entry) { e.ID = "" }, "missing id"},
+		{"no modified", func(e *Entry) { e.Modified = time.Time{} }, "missing modified time"},
+		{"bad alias", func(e *Entry) { e.Aliases = []string{"CVE-24-1"} }, `alias "CVE-24-1" is not`},
+		{"no ecosystem", func(e *Entry) { e.Affected[0].Module.Ecosystem = "" }, `affected[0]: ecosystem is ""`},
+		{"no package path", func(e *Entry) { e.Affected[0].EcosystemSpecific.Packages[0].Path = "" }, "imports[0]: missing package path"},
+		{"no url", func(e *Entry) { e.References[0].URL = "" }, "references[0]: missing url"},
+		{"bad cwe", func(e *Entry) { e.DatabaseSpecific.CWEIDs = []string{"79"} }, `"79" is not a CWE ID`},
+		{"v prefix", func(e *Entry) { e.Affected[0].Ranges[0].Events[1].Fixed = "v1.0.0" }, `"v1.0.0" is not a semantic version`},
+		{"fixed before introduced", func(e *Entry) { e.Affected[0].Ranges[0].Events[3].Fixed = "0.9.0" }, "0.9.0 does not come after 1.0.0"},
+		{"prerelease order", func(e *Entry) { e.Affected[0].Ranges[0].Events[2].Introduced = "1.0.0-rc.1" }, "1.0.0-rc.1 does not come after 1.0.0-rc.1"},
+		{"no introduced", func(e *Entry) { e.Affected[0].Ranges[0].Events = []RangeEvent{{Fixed: "1.0.0"}} }, "event 0 fixes 1.0.0"},
+		{"both", func(e *Entry) { e.Affected[0].Ranges[0].Events[0].Fixed = "1.0.0" }, "exactly one of introduced and fixed"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			e := valid()
+			test.modify(e)
+			err := e.Validate()
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestValidSemver(t *testing.T) {
+	for _, v := range []string{"1.0.0", "1.0.0-rc.1", "1.0.0+build.1", "1.10.0-beta.11"} {
+		if !validSemver(v) {
+			t.Errorf("validSemver(%q) = false, want true", v)
+		}
+	}
+	for _, v := range []string{"1", "1.0", "01.0.0", "1.0.0-01", "1.0.0-", "1.0.0+", "v1.0.0"} {
+		if validSemver(v) {
+			t.Errorf("validSemver(%q) = true, want false", v)
+		}
+	}
+}
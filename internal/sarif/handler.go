@@ -44,13 +44,27 @@ func (h *handler) Config(c *govulncheck.Config) error {
 }
 
 func (h *handler) Progress(p *govulncheck.Progress) error {
-	return nil // not needed by sarif
+	if p.SymbolsFailed && h.cfg != nil {
+		// The findings are those of a package level scan.
+		lowered := *h.cfg
+		lowered.ScanLevel = govulncheck.ScanLevelPackage
+		h.cfg = &lowered
+	}
+	return nil // other progress is not needed by sarif
 }
 
 func (h *handler) SBOM(s *govulncheck.SBOM) error {
 	return nil // not needed by sarif
 }
 
+func (h *handler) Graph(g *govulncheck.Graph) error {
+	return nil // not needed by sarif
+}
+
+func (h *handler) Timing(t *govulncheck.Timing) error {
+	return nil // not needed by sarif
+}
+
 func (h *handler) OSV(e *osv.Entry) error {
 	h.osvs[e.ID] = e
 	return nil
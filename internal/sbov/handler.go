@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sbov
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/semver"
+)
+
+type handler struct {
+	w        io.Writer
+	cfg      *govulncheck.Config
+	sbom     *govulncheck.SBOM
+	osvs     []*osv.Entry
+	findings []*govulncheck.Finding
+}
+
+// NewHandler returns a handler that writes the SBOV of the scan to w.
+func NewHandler(w io.Writer) *handler {
+	return &handler{w: w}
+}
+
+func (h *handler) Config(cfg *govulncheck.Config) error {
+	h.cfg = cfg
+	return nil
+}
+
+func (h *handler) Progress(p *govulncheck.Progress) error {
+	if p.SymbolsFailed && h.cfg != nil {
+		// The findings are those of a package level scan.
+		lowered := *h.cfg
+		lowered.ScanLevel = govulncheck.ScanLevelPackage
+		h.cfg = &lowered
+	}
+	return nil // other progress is not part of the SBOV
+}
+
+func (h *handler) SBOM(s *govulncheck.SBOM) error {
+	h.sbom = s
+	return nil
+}
+
+func (h *handler) OSV(e *osv.Entry) error {
+	h.osvs = append(h.osvs, e)
+	return nil
+}
+
+func (h *handler) Finding(f *govulncheck.Finding) error {
+	h.findings = append(h.findings, f)
+	return nil
+}
+
+func (h *handler) Graph(g *govulncheck.Graph) error {
+	return nil // not part of the SBOV
+}
+
+func (h *handler) Timing(t *govulncheck.Timing) error {
+	return nil // not part of the SBOV
+}
+
+// Flush writes the SBOV. Like the other machine readable
+// formats, it does not report vulnerabilities with an error.
+func (h *handler) Flush() error {
+	out, err := json.MarshalIndent(toSBOV(h), "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+	_, err = h.w.Write(out)
+	return err
+}
+
+// moduleVersion is a module at a version.
+type moduleVersion struct{ path, version string }
+
+func toSBOV(h *handler) *Document {
+	doc := &Document{Modules: []*Module{}}
+	if h.cfg != nil {
+		doc.ScannerName = h.cfg.ScannerName
+		doc.ScannerVersion = h.cfg.ScannerVersion
+		doc.DB = h.cfg.DB
+		doc.DBLastModified = h.cfg.DBLastModified
+		doc.GoVersion = h.cfg.GoVersion
+		doc.ScanLevel = h.cfg.ScanLevel
+	}
+
+	var mods []moduleVersion
+	if h.sbom != nil {
+		for _, m := range h.sbom.Modules {
+			mods = append(mods, moduleVersion{m.Path, m.Version})
+		}
+		// The SBOM of a binary has the Go version, but
+		// not the standard library module.
+		if h.sbom.GoVersion != "" && !slices.ContainsFunc(mods, func(m moduleVersion) bool {
+			return m.path == internal.GoStdModulePath
+		}) {
+			mods = append(mods, moduleVersion{internal.GoStdModulePath, semver.GoTagToSemver(h.sbom.GoVersion)})
+		}
+	}
+	// Findings name the module versions actually used,
+	// which the SBOM may not list, for instance in binaries.
+	status := make(map[moduleVersion]map[string]*Advisory)
+	for _, f := range h.findings {
+		mv := moduleVersion{f.Trace[0].Module, f.Trace[0].Version}
+		if status[mv] == nil {
+			status[mv] = make(map[string]*Advisory)
+			mods = append(mods, mv)
+		}
+		a := status[mv][f.OSV]
+		if a == nil {
+			a = &Advisory{ID: f.OSV, Status: StatusRequired, FixedVersion: f.FixedVersion}
+			status[mv][f.OSV] = a
+		}
+		if s := findingStatus(f); rank(s) > rank(a.Status) {
+			a.Status = s
+		}
+	}
+	slices.SortFunc(mods, func(a, b moduleVersion) int {
+		if c := strings.Compare(a.path, b.path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.version, b.version)
+	})
+	mods = slices.Compact(mods)
+
+	for _, mv := range mods {
+		m := &Module{Path: mv.path, Version: mv.version, Advisories: []*Advisory{}}
+		for _, a := range status[mv] {
+			m.Advisories = append(m.Advisories, a)
+		}
+		for _, e := range h.osvs {
+			if a := status[mv][e.ID]; a != nil {
+				a.Aliases = e.Aliases
+			} else if affects(e, mv.path) {
+				m.Advisories = append(m.Advisories, &Advisory{ID: e.ID, Aliases: e.Aliases, Status: StatusNone})
+			}
+		}
+		slices.SortFunc(m.Advisories, func(a, b *Advisory) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+		doc.Modules = append(doc.Modules, m)
+	}
+	return doc
+}
+
+// affects reports whether e is an advisory for module path.
+func affects(e *osv.Entry, path string) bool {
+	for _, a := range e.Affected {
+		if a.Module.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// findingStatus returns the status of an advisory with finding f.
+func findingStatus(f *govulncheck.Finding) Status {
+	switch frame := f.Trace[0]; {
+	case frame.Function != "":
+		return StatusCalled
+	case frame.Package != "":
+		return StatusImported
+	default:
+		return StatusRequired
+	}
+}
+
+// rank orders statuses from the least to the most precise.
+func rank(s Status) int {
+	return slices.Index([]Status{StatusNone, StatusRequired, StatusImported, StatusCalled}, s)
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sbov
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestToSBOV(t *testing.T) {
+	h := NewHandler(nil)
+	h.Config(&govulncheck.Config{ScannerName: "govulncheck", ScanLevel: govulncheck.ScanLevelSymbol})
+	// The SBOM of a binary does not list the standard library.
+	h.SBOM(&govulncheck.SBOM{
+		GoVersion: "go1.21.1",
+		Modules: []*govulncheck.Module{
+			{Path: "example.com/main"},
+			{Path: "golang.org/x/text", Version: "v0.3.0"},
+		},
+	})
+	for _, e := range []*osv.Entry{
+		{ID: "GO-0000-0001", Aliases: []string{"CVE-0000-0001"}, Affected: []osv.Affected{{Module: osv.Module{Path: "golang.org/x/text"}}}},
+		{ID: "GO-0000-0002", Affected: []osv.Affected{{Module: osv.Module{Path: "golang.org/x/text"}}}},
+		{ID: "GO-0000-0003", Affected: []osv.Affected{{Module: osv.Module{Path: "stdlib"}}}},
+	} {
+		h.OSV(e)
+	}
+	text := &govulncheck.Frame{Module: "golang.org/x/text", Version: "v0.3.0"}
+	for _, f := range []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", FixedVersion: "v0.3.7", Trace: []*govulncheck.Frame{text}},
+		{OSV: "GO-0000-0001", FixedVersion: "v0.3.7", Trace: []*govulncheck.Frame{{Module: text.Module, Version: text.Version, Package: "golang.org/x/text/language", Function: "Parse"}}},
+		{OSV: "GO-0000-0001", FixedVersion: "v0.3.7", Trace: []*govulncheck.Frame{{Module: text.Module, Version: text.Version, Package: "golang.org/x/text/language"}}},
+	} {
+		h.Finding(f)
+	}
+
+	want := &Document{
+		ScannerName: "govulncheck",
+		ScanLevel:   govulncheck.ScanLevelSymbol,
+		Modules: []*Module{
+			{Path: "example.com/main", Advisories: []*Advisory{}},
+			{Path: "golang.org/x/text", Version: "v0.3.0", Advisories: []*Advisory{
+				{ID: "GO-0000-0001", Aliases: []string{"CVE-0000-0001"}, Status: StatusCalled, FixedVersion: "v0.3.7"},
+				{ID: "GO-0000-0002", Status: StatusNone},
+			}},
+			{Path: "stdlib", Version: "v1.21.1", Advisories: []*Advisory{
+				{ID: "GO-0000-0003", Status: StatusNone},
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, toSBOV(h)); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
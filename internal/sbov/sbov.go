@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sbov defines the Software Bill of Vulnerabilities (SBOV)
+// output of govulncheck.
+//
+// An SBOV is an inventory of every module in a build, clean ones
+// included, with the advisories of the vulnerability database for
+// each module and how they apply to it. Unlike the findings of the
+// other formats, it describes the complete vulnerability posture of
+// the scanned code, meant to be stored and compared over time.
+package sbov
+
+import (
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// Status is how an advisory applies to a module version.
+type Status string
+
+const (
+	// StatusCalled means that vulnerable symbols are called.
+	StatusCalled Status = "called"
+	// StatusImported means that vulnerable packages are imported,
+	// but their vulnerable symbols are not known to be called.
+	StatusImported Status = "imported"
+	// StatusRequired means that the vulnerable module version is
+	// required, but its vulnerable packages are not known to be
+	// imported.
+	StatusRequired Status = "required"
+	// StatusNone means that the module version is not affected.
+	StatusNone Status = "none"
+)
+
+// Document is a Software Bill of Vulnerabilities.
+type Document struct {
+	// ScannerName and ScannerVersion identify the tool
+	// that produced the document.
+	ScannerName    string `json:"scanner_name"`
+	ScannerVersion string `json:"scanner_version,omitempty"`
+
+	// DB is the vulnerability database used for the scan, and
+	// DBLastModified the time of its last modification.
+	DB             string     `json:"db,omitempty"`
+	DBLastModified *time.Time `json:"db_last_modified,omitempty"`
+
+	// GoVersion is the Go version of the standard library.
+	GoVersion string `json:"go_version,omitempty"`
+
+	// ScanLevel is the level of the scan, which bounds the
+	// precision of the statuses: with a module level scan, no
+	// advisory is called or imported.
+	ScanLevel govulncheck.ScanLevel `json:"scan_level,omitempty"`
+
+	// Modules are the modules of the build, sorted
+	// by path and version.
+	Modules []*Module `json:"modules"`
+}
+
+// Module is a module of the build.
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+
+	// Advisories are the advisories of the database
+	// for the module, sorted by ID. It is empty for
+	// modules without any known vulnerability.
+	Advisories []*Advisory `json:"advisories"`
+}
+
+// Advisory is an advisory of the vulnerability database
+// for a module, and how it applies to the module version.
+type Advisory struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	Status  Status   `json:"status"`
+
+	// FixedVersion is the lowest version of the module
+	// that is not affected, if the module is affected
+	// and a fix is available.
+	FixedVersion string `json:"fixed_version,omitempty"`
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// archiveSep separates the path of an archive from the path
+// of a binary inside it, as in "dist/app.tar.gz//bin/app".
+const archiveSep = "//"
+
+// splitArchivePath splits p into the path of a tar or zip archive
+// and the path of a file inside it. It reports false if p does not
+// have this form.
+func splitArchivePath(p string) (archive, member string, ok bool) {
+	archive, member, ok = strings.Cut(p, archiveSep)
+	if !ok || member == "" || archiveFormat(archive) == "" {
+		return "", "", false
+	}
+	return archive, path.Clean(member), true
+}
+
+// archiveFormat returns the format of the archive at path,
+// based on its extension, or "" if it is not a supported archive.
+func archiveFormat(path string) string {
+	switch p := strings.ToLower(path); {
+	case strings.HasSuffix(p, ".zip"):
+		return "zip"
+	case strings.HasSuffix(p, ".tar"):
+		return "tar"
+	case strings.HasSuffix(p, ".tar.gz"), strings.HasSuffix(p, ".tgz"):
+		return "tar.gz"
+	}
+	return ""
+}
+
+// extractArchiveMember copies member of archive to a temporary
+// file and returns its path. The binary readers need random access,
+// which compressed tar streams do not provide. The caller must
+// remove the file.
+func extractArchiveMember(archive, member string) (_ string, err error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var r io.Reader
+	switch archiveFormat(archive) {
+	case "zip":
+		fi, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		zr, err := zip.NewReader(f, fi.Size())
+		if err != nil {
+			return "", err
+		}
+		for _, zf := range zr.File {
+			if path.Clean(zf.Name) == member {
+				rc, err := zf.Open()
+				if err != nil {
+					return "", err
+				}
+				defer rc.Close()
+				r = rc
+				break
+			}
+		}
+	case "tar.gz":
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", err
+		}
+		defer gr.Close()
+		r, err = findTarMember(gr, member)
+		if err != nil {
+			return "", err
+		}
+	case "tar":
+		r, err = findTarMember(f, member)
+		if err != nil {
+			return "", err
+		}
+	}
+	if r == nil {
+		return "", fmt.Errorf("%s not found in %s", member, archive)
+	}
+
+	tmp, err := os.CreateTemp("", "govulncheck-bin-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// findTarMember returns a reader for the contents of the regular
+// file member of the tar stream r, or nil if there is no such file.
+func findTarMember(r io.Reader, member string) (io.Reader, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Clean(hdr.Name) == member {
+			return tr, nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitArchivePath(t *testing.T) {
+	for _, test := range []struct {
+		path            string
+		archive, member string
+		ok              bool
+	}{
+		{"dist/app.tar.gz//bin/app", "dist/app.tar.gz", "bin/app", true},
+		{"app.TGZ//./app", "app.TGZ", "app", true},
+		{"app.zip//app.exe", "app.zip", "app.exe", true},
+		{"app.tar//app", "app.tar", "app", true},
+		{"app.tar.gz", "", "", false},
+		{"app.tar.gz//", "", "", false},
+		{"dir//app", "", "", false},
+	} {
+		archive, member, ok := splitArchivePath(test.path)
+		if archive != test.archive || member != test.member || ok != test.ok {
+			t.Errorf("splitArchivePath(%q) = %q, %q, %t, want %q, %q, %t",
+				test.path, archive, member, ok, test.archive, test.member, test.ok)
+		}
+	}
+}
+
+func TestExtractArchiveMember(t *testing.T) {
+	const content = "binary contents"
+	dir := t.TempDir()
+	create := func(name string) *os.File {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	tf := create("app.tar.gz")
+	gw := gzip.NewWriter(tf)
+	tw := tar.NewWriter(gw)
+	for _, name := range []string{"README", "bin/app"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []interface{ Close() error }{tw, gw, tf} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	zf := create("app.zip")
+	zw := zip.NewWriter(zf)
+	w, err := zw.Create("bin/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []interface{ Close() error }{zw, zf} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"app.tar.gz", "app.zip"} {
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(dir, name)
+			file, err := extractArchiveMember(archive, "bin/app")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(file)
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != content {
+				t.Errorf("extracted %q, want %q", got, content)
+			}
+			if _, err := extractArchiveMember(archive, "bin/other"); err == nil {
+				t.Error("extracting a missing member succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/semver"
+)
+
+// versionsFlag is used for parsing the repeatable
+// govulncheck -assume-version flag. It maps module
+// paths to semantic versions.
+type versionsFlag map[string]string
+
+func (v *versionsFlag) Set(s string) error {
+	mod, version, ok := strings.Cut(s, "@")
+	if !ok || mod == "" {
+		return fmt.Errorf("want module@version, got %q", s)
+	}
+	if !strings.HasPrefix(version, "v") || !semver.Valid(version) {
+		// Go versions, such as go1.21.3, are accepted
+		// for the standard library and the toolchain.
+		if version = semver.GoTagToSemver(version); version == "" {
+			return fmt.Errorf("invalid version in %q", s)
+		}
+	}
+	if *v == nil {
+		*v = make(versionsFlag)
+	}
+	if _, ok := (*v)[mod]; ok {
+		return fmt.Errorf("duplicate version for module %s", mod)
+	}
+	(*v)[mod] = version
+	return nil
+}
+
+func (v *versionsFlag) Get() interface{} { return *v }
+func (v *versionsFlag) String() string   { return "" }
+
+// warnAssumedModules records a warning in cfg for each module of
+// -assume-version that is not among mods or their replacements, as
+// its assumed version then has no effect.
+func warnAssumedModules(cfg *config, mods []*packages.Module) {
+	known := map[string]bool{internal.GoStdModulePath: true}
+	for _, m := range mods {
+		if m == nil {
+			continue
+		}
+		known[m.Path] = true
+		if m.Replace != nil {
+			known[m.Replace.Path] = true
+		}
+	}
+	var unknown []string
+	for mod := range cfg.AssumedVersions {
+		if !known[mod] {
+			unknown = append(unknown, mod)
+		}
+	}
+	sort.Strings(unknown)
+	for _, mod := range unknown {
+		cfg.warnf("-assume-version: module %s is not used by the scanned code", mod)
+	}
+}
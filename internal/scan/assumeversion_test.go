@@ -0,0 +1,49 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestWarnAssumedModules(t *testing.T) {
+	mods := []*packages.Module{
+		{Path: "example.com/a"},
+		{Path: "example.com/b", Replace: &packages.Module{Path: "example.com/fork"}},
+		nil, // no main module
+	}
+	cfg := &config{}
+	cfg.AssumedVersions = map[string]string{
+		"example.com/a":    "v1.0.0",
+		"example.com/fork": "v1.0.0",
+		"example.com/z":    "v1.0.0",
+		"example.com/c":    "v1.0.0",
+		"stdlib":           "v1.21.0",
+	}
+	warnAssumedModules(cfg, mods)
+
+	handler := test.NewMockHandler()
+	if err := emitWarnings(handler, cfg); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range handler.ProgressMessages {
+		got = append(got, p.Message)
+	}
+	want := []string{
+		"warning: -assume-version: module example.com/c is not used by the scanned code",
+		"warning: -assume-version: module example.com/z is not used by the scanned code",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if cfg.warnings != nil {
+		t.Errorf("warnings not cleared: %v", cfg.warnings)
+	}
+}
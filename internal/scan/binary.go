@@ -10,6 +10,7 @@ import (
 	"errors"
 	"os"
 	"runtime/debug"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal/buildinfo"
@@ -23,10 +24,26 @@ import (
 func runBinary(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client) (err error) {
 	defer derrors.Wrap(&err, "govulncheck")
 
-	bin, err := createBin(cfg.patterns[0])
+	start := time.Now()
+	path := cfg.patterns[0]
+	if archive, member, ok := splitArchivePath(path); ok {
+		path, err = extractArchiveMember(archive, member)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(path)
+	}
+	bin, err := createBin(path)
 	if err != nil {
 		return err
 	}
+	warnAssumedModules(cfg, append(bin.Modules, bin.Main))
+	if err := emitWarnings(handler, cfg); err != nil {
+		return err
+	}
+	if err := vulncheck.EmitTiming(handler, &cfg.Config, "read binary", time.Since(start)); err != nil {
+		return err
+	}
 
 	p := &govulncheck.Progress{Message: binaryProgressMessage}
 	if err := handler.Progress(p); err != nil {
@@ -0,0 +1,34 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"golang.org/x/vuln/internal/test"
+)
+
+// TestCreateBinPlatform checks that the target platform of a
+// cross-compiled binary is extracted from its build info.
+func TestCreateBinPlatform(t *testing.T) {
+	for _, p := range []struct{ goos, goarch string }{
+		{"windows", "arm64"},
+		{"linux", "386"},
+		{"darwin", "amd64"},
+	} {
+		t.Run(p.goos+"-"+p.goarch, func(t *testing.T) {
+			binary, done := test.GoBuild(t, "testdata/platform", "", false, "GOOS", p.goos, "GOARCH", p.goarch)
+			defer done()
+
+			bin, err := createBin(binary)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bin.GOOS != p.goos || bin.GOARCH != p.goarch {
+				t.Errorf("got %s/%s, want %s/%s", bin.GOOS, bin.GOARCH, p.goos, p.goarch)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// resultCacheFile returns the file in the -cache-dir directory
+// holding the results of a module level scan of the main module
+// in dir with the settings of cfg, or "" if the results cannot be
+// cached.
+//
+// The file is named after a hash of the go.mod and go.sum files
+// of the module, which determine the required modules, and of the
+// configuration of the scan, which includes the Go version and the
+// last modification time of the database. A scan with the same
+// hash therefore reports the same findings.
+func resultCacheFile(cfg *config, dir string) (string, error) {
+	if cfg.DBLastModified == nil {
+		return "", nil // new advisories could not be detected
+	}
+	// In a workspace, the required modules depend
+	// on the go.mod files of all its modules.
+	if goworkFile(dir, cfg.env) != "" {
+		return "", nil
+	}
+	gomod := gomodFile(dir, cfg.env)
+	if gomod == "" {
+		return "", nil
+	}
+	h := sha256.New()
+	for _, file := range []string{gomod, strings.TrimSuffix(gomod, ".mod") + ".sum"} {
+		data, err := os.ReadFile(file)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %d\n", filepath.Base(file), len(data))
+		h.Write(data)
+	}
+	b, err := json.Marshal(&cfg.Config)
+	if err != nil {
+		return "", err
+	}
+	h.Write(b)
+	return filepath.Join(cfg.cacheDir, hex.EncodeToString(h.Sum(nil))+".json"), nil
+}
+
+var cacheProgressMessage = &govulncheck.Progress{
+	Message: "Reusing the results of a previous scan of the same dependencies...",
+}
+
+// readResultCache returns the results cached in file,
+// or nil if there are none.
+func readResultCache(file string) ([]byte, error) {
+	if file == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// cacheHandler wraps a handler and, on Flush, writes the SBOM,
+// OSV entries and findings of the stream to a result cache file,
+// see -cache-dir. Progress and timings of the scan that produced
+// the results are not cached.
+type cacheHandler struct {
+	govulncheck.Handler
+	file string
+	buf  bytes.Buffer
+	json govulncheck.Handler
+}
+
+func newCacheHandler(h govulncheck.Handler, file string) *cacheHandler {
+	ch := &cacheHandler{Handler: h, file: file}
+	ch.json = govulncheck.NewJSONHandler(&ch.buf)
+	return ch
+}
+
+func (h *cacheHandler) SBOM(s *govulncheck.SBOM) error {
+	if err := h.json.SBOM(s); err != nil {
+		return err
+	}
+	return h.Handler.SBOM(s)
+}
+
+func (h *cacheHandler) OSV(e *osv.Entry) error {
+	if err := h.json.OSV(e); err != nil {
+		return err
+	}
+	return h.Handler.OSV(e)
+}
+
+func (h *cacheHandler) Finding(f *govulncheck.Finding) error {
+	if err := h.json.Finding(f); err != nil {
+		return err
+	}
+	return h.Handler.Finding(f)
+}
+
+// Flush writes the cache file before flushing the wrapped handler,
+// so that the results are cached even if vulnerabilities are found.
+// The file is renamed into place, so that concurrent scans never
+// read a partially written file.
+func (h *cacheHandler) Flush() error {
+	if err := writeResultCache(h.file, h.buf.Bytes()); err != nil {
+		return fmt.Errorf("writing result cache: %w", err)
+	}
+	return Flush(h.Handler)
+}
+
+func writeResultCache(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(file), "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), file)
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestResultCacheFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/m\n\ngo 1.22\n\nrequire golang.org/x/text v0.3.0\n")
+	env := append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	modified := time.Date(2023, 4, 3, 15, 57, 51, 0, time.UTC)
+	cfg := &config{env: env, cacheDir: "cache"}
+	cfg.ScanLevel = govulncheck.ScanLevelModule
+	cfg.DBLastModified = &modified
+
+	file := func() string {
+		t.Helper()
+		f, err := resultCacheFile(cfg, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	first := file()
+	if filepath.Dir(first) != "cache" {
+		t.Fatalf("resultCacheFile = %s, want a file in cache", first)
+	}
+	if again := file(); again != first {
+		t.Errorf("resultCacheFile of the same module = %s, want %s", again, first)
+	}
+
+	write("go.sum", "golang.org/x/text v0.3.0 h1:g61tztE5qeGQ89tm6NTjjM9VPIm088od1l6aSorWRWg=\n")
+	withSum := file()
+	if withSum == first {
+		t.Error("resultCacheFile did not change with go.sum")
+	}
+
+	cfg.GoVersion = "go1.22.4"
+	if file() == withSum {
+		t.Error("resultCacheFile did not change with the Go version")
+	}
+
+	later := modified.Add(time.Hour)
+	cfg.DBLastModified = &later
+	if file() == withSum {
+		t.Error("resultCacheFile did not change with the database")
+	}
+
+	cfg.DBLastModified = nil
+	if f := file(); f != "" {
+		t.Errorf("resultCacheFile without database modification time = %s, want none", f)
+	}
+}
+
+func TestCacheHandler(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cache", "results.json")
+	entry := &osv.Entry{ID: "GO-0000-0001"}
+	finding := &govulncheck.Finding{
+		OSV:   "GO-0000-0001",
+		Trace: []*govulncheck.Frame{{Module: "example.com/m", Version: "v1.0.0"}},
+	}
+
+	scanned := test.NewMockHandler()
+	h := newCacheHandler(scanned, file)
+	if err := h.Progress(&govulncheck.Progress{Message: "scanning"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.OSV(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Finding(finding); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	cached, err := readResultCache(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := test.NewMockHandler()
+	if err := govulncheck.HandleJSON(bytes.NewReader(cached), replayed); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(scanned.OSVMessages, replayed.OSVMessages); diff != "" {
+		t.Errorf("cached OSV entries mismatch (-scanned, +replayed):\n%s", diff)
+	}
+	if diff := cmp.Diff(scanned.FindingMessages, replayed.FindingMessages); diff != "" {
+		t.Errorf("cached findings mismatch (-scanned, +replayed):\n%s", diff)
+	}
+	if len(replayed.ProgressMessages) != 0 {
+		t.Errorf("cached progress messages = %v, want none", replayed.ProgressMessages)
+	}
+}
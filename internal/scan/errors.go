@@ -37,6 +37,16 @@ govulncheck with the current Go version.`)
 govulncheck only works with Go modules. Try navigating to your module directory.
 Otherwise, run go mod init to make your project a module.
 
+See https://go.dev/doc/modules/managing-dependencies for more information.`)
+
+	// errNoGoSum indicates that packages could not be loaded
+	// because the go.sum file is missing or incomplete.
+	errNoGoSum = errors.New(`missing go.sum entries
+
+Loading packages failed because the go.sum file of your module is missing or
+does not list all the modules it needs. Try running go mod tidy, or
+go mod download for the modules reported below.
+
 See https://go.dev/doc/modules/managing-dependencies for more information.`)
 
 	// errNoBinaryFlag indicates that govulncheck was run on a file, without
@@ -65,3 +75,9 @@ func isGoVersionMismatchError(err error) bool {
 	return strings.Contains(msg, "This application uses version go") &&
 		strings.Contains(msg, "It may fail to process source files")
 }
+
+// isMissingGoSumError checks if err is due to missing go.sum entries.
+func isMissingGoSumError(err error) bool {
+	// See cmd/go/internal/modload.
+	return strings.Contains(err.Error(), "missing go.sum entry")
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// failLevels maps path prefixes of the packages of the scanned code
+// to the finding level at which vulnerabilities found through those
+// packages are reported as found, see -fail-on-modules. A prefix is
+// either a full import path or a path relative to a main module.
+type failLevels map[string]govulncheck.ScanLevel
+
+// level returns the fail level of the scanned package pkg, which is
+// the level of the longest prefix of pkg in l, or def if there is
+// none. Prefixes match whole path elements: golang.org/x matches
+// golang.org/x/text, but not golang.org/xyz. Relative prefixes only
+// match once l is resolved against the main modules.
+func (l failLevels) level(pkg string, def govulncheck.ScanLevel) govulncheck.ScanLevel {
+	for p := pkg; p != "." && p != ""; p = path.Dir(p) {
+		if level, ok := l[p]; ok {
+			return level
+		}
+	}
+	return def
+}
+
+// resolve returns the fail levels of l with each prefix also joined to
+// each of the main modules, so that internal/crypto matches the package
+// example.com/m/internal/crypto of main module example.com/m. Prefixes
+// given as full paths take precedence over joined ones.
+func (l failLevels) resolve(mainModules []string) failLevels {
+	if l == nil {
+		return nil
+	}
+	resolved := make(failLevels, len(l))
+	for p, level := range l {
+		resolved[p] = level
+	}
+	for p, level := range l {
+		for _, m := range mainModules {
+			if _, ok := l[m+"/"+p]; !ok {
+				resolved[m+"/"+p] = level
+			}
+		}
+	}
+	return resolved
+}
+
+// scannedPackage returns the package of the scanned code through which
+// the vulnerability of f is found: the package of the entry function of
+// a call stack, or the root package of an import chain. It returns ""
+// for module level findings and the findings of binaries, which have
+// neither.
+func scannedPackage(f *govulncheck.Finding) string {
+	if len(f.Trace) > 1 {
+		return f.Trace[len(f.Trace)-1].Package
+	}
+	if len(f.ImportChain) > 0 {
+		return f.ImportChain[len(f.ImportChain)-1]
+	}
+	return ""
+}
+
+// vulnerabilitiesFound reports whether findings include vulnerabilities
+// found at level, or at the level of the scanned package they are found
+// through in pkgLevels.
+func vulnerabilitiesFound(findings []*findingSummary, level govulncheck.ScanLevel, pkgLevels failLevels) bool {
+	for _, f := range findings {
+		fs := []*findingSummary{f}
+		switch pkgLevels.level(scannedPackage(f.Finding), level) {
+		case govulncheck.ScanLevelSymbol:
+			if isCalled(fs) {
+				return true
+			}
+		case govulncheck.ScanLevelPackage:
+			if isImported(fs) {
+				return true
+			}
+		case govulncheck.ScanLevelModule:
+			if isRequired(fs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withoutSymbols returns level, lowered to the package level if it is
+// the symbol level.
+func withoutSymbols(level govulncheck.ScanLevel) govulncheck.ScanLevel {
+	if level == govulncheck.ScanLevelSymbol {
+		return govulncheck.ScanLevelPackage
+	}
+	return level
+}
+
+// withoutSymbols returns the fail levels of l, with the symbol level
+// lowered to the package level.
+func (l failLevels) withoutSymbols() failLevels {
+	if l == nil {
+		return nil
+	}
+	lowered := make(failLevels, len(l))
+	for p, level := range l {
+		lowered[p] = withoutSymbols(level)
+	}
+	return lowered
+}
+
+// readFailLevels reads the file of fail levels. Each non-empty
+// line of the file has the form
+//
+//	package/path/prefix level
+//
+// where level is one of module, package, or symbol. A prefix can end
+// with /**, which is the same as the prefix without it, but cannot
+// otherwise contain glob patterns. Lines starting with # are comments.
+func readFailLevels(file string) (failLevels, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return parseFailLevels(file, data)
+}
+
+func parseFailLevels(file string, data []byte) (failLevels, error) {
+	levels := make(failLevels)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want \"path level\", got %q", file, n, line)
+		}
+		// Prefixes match whole path elements anyway, so
+		// allow the glob pattern matching everything below.
+		prefix, level := strings.TrimSuffix(strings.TrimSuffix(fields[0], "**"), "/"), fields[1]
+		if strings.ContainsAny(prefix, "*?[") {
+			return nil, fmt.Errorf("%s:%d: invalid path prefix %q, only a trailing /** is supported", file, n, fields[0])
+		}
+		if _, ok := supportedLevels[level]; !ok {
+			return nil, fmt.Errorf("%s:%d: invalid level %q for path %s, want module, package, or symbol", file, n, level, prefix)
+		}
+		if _, ok := levels[prefix]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate level for path %s", file, n, prefix)
+		}
+		levels[prefix] = govulncheck.ScanLevel(level)
+	}
+	return levels, scanner.Err()
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+func TestParseFailLevels(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		in      string
+		want    failLevels
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			in: `# crypto code fails on anything
+golang.org/x/crypto module
+github.com/tidwall/ symbol
+`,
+			want: failLevels{
+				"golang.org/x/crypto": govulncheck.ScanLevelModule,
+				"github.com/tidwall":  govulncheck.ScanLevelSymbol,
+			},
+		},
+		{
+			name: "glob",
+			in:   "internal/crypto/** module\ntools/** symbol\n",
+			want: failLevels{
+				"internal/crypto": govulncheck.ScanLevelModule,
+				"tools":           govulncheck.ScanLevelSymbol,
+			},
+		},
+		{
+			name:    "unsupported glob",
+			in:      "golang.org/x/*/crypto module\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid level",
+			in:      "golang.org/x/crypto critical\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing level",
+			in:      "golang.org/x/crypto\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate",
+			in:      "golang.org/x/crypto module\ngolang.org/x/crypto/ package\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseFailLevels("levels.txt", []byte(test.in))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); !test.wantErr && diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFailLevel(t *testing.T) {
+	levels := failLevels{
+		"golang.org/x":        govulncheck.ScanLevelSymbol,
+		"golang.org/x/crypto": govulncheck.ScanLevelModule,
+	}
+	for _, test := range []struct {
+		pkg  string
+		want govulncheck.ScanLevel
+	}{
+		{"golang.org/x/crypto", govulncheck.ScanLevelModule},
+		{"golang.org/x/crypto/v2", govulncheck.ScanLevelModule},
+		{"golang.org/x/text", govulncheck.ScanLevelSymbol},
+		{"golang.org/xyz", govulncheck.ScanLevelPackage},
+		{"stdlib", govulncheck.ScanLevelPackage},
+	} {
+		if got := levels.level(test.pkg, govulncheck.ScanLevelPackage); got != test.want {
+			t.Errorf("level(%q) = %s, want %s", test.pkg, got, test.want)
+		}
+	}
+}
+
+func TestResolveFailLevels(t *testing.T) {
+	levels := failLevels{
+		"internal/crypto":                 govulncheck.ScanLevelModule,
+		"tools":                           govulncheck.ScanLevelSymbol,
+		"example.com/m/tools":             govulncheck.ScanLevelPackage,
+		"golang.org/x/crypto/internal/ec": govulncheck.ScanLevelModule,
+	}
+	got := levels.resolve([]string{"example.com/m"})
+	for _, test := range []struct {
+		pkg  string
+		want govulncheck.ScanLevel
+	}{
+		{"example.com/m/internal/crypto/aes", govulncheck.ScanLevelModule},
+		// A full path takes precedence over a joined relative one.
+		{"example.com/m/tools/gen", govulncheck.ScanLevelPackage},
+		{"example.com/m/cmd", govulncheck.ScanLevelSymbol},
+		{"golang.org/x/crypto/internal/ec", govulncheck.ScanLevelModule},
+		{"example.com/other/internal/crypto", govulncheck.ScanLevelSymbol},
+	} {
+		if level := got.level(test.pkg, govulncheck.ScanLevelSymbol); level != test.want {
+			t.Errorf("level(%q) = %s, want %s", test.pkg, level, test.want)
+		}
+	}
+}
+
+func TestVulnerabilitiesFound(t *testing.T) {
+	// Imported through the scanned package example.com/m/internal/crypto.
+	imported := &govulncheck.Finding{
+		OSV:         "GO-0001",
+		Trace:       []*govulncheck.Frame{{Module: "golang.org/x/text", Version: "v0.3.0", Package: "golang.org/x/text/language"}},
+		ImportChain: []string{"golang.org/x/text/language", "example.com/m/internal/crypto"},
+	}
+	levels := failLevels{
+		"internal/crypto/aes": govulncheck.ScanLevelPackage,
+		"tools":               govulncheck.ScanLevelSymbol,
+	}
+	for _, test := range []struct {
+		name     string
+		findings []*govulncheck.Finding
+		levels   failLevels
+		want     bool
+	}{
+		{"imported, no levels", []*govulncheck.Finding{imported}, nil, false},
+		{"imported, other package", []*govulncheck.Finding{imported}, levels, false},
+		{"imported, matching package", []*govulncheck.Finding{imported}, failLevels{"internal/crypto": govulncheck.ScanLevelPackage}, true},
+		{"imported, matching full path", []*govulncheck.Finding{imported}, failLevels{"example.com/m/internal": govulncheck.ScanLevelModule}, true},
+		// The vulnerable dependency is not the scanned code.
+		{"imported, dependency path", []*govulncheck.Finding{imported}, failLevels{"golang.org/x/text": govulncheck.ScanLevelPackage}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var fs []*findingSummary
+			for _, f := range test.findings {
+				fs = append(fs, newFindingSummary(f))
+			}
+			levels := test.levels.resolve([]string{"example.com/m"})
+			if got := vulnerabilitiesFound(fs, govulncheck.ScanLevelSymbol, levels); got != test.want {
+				t.Errorf("vulnerabilitiesFound() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScannedPackage(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    *govulncheck.Finding
+		want string
+	}{
+		{
+			name: "call stack",
+			f: &govulncheck.Finding{Trace: []*govulncheck.Frame{
+				{Module: "golang.org/x/text", Package: "golang.org/x/text/language", Function: "Parse"},
+				{Module: "example.com/m", Package: "example.com/m/internal/lang", Function: "Tag"},
+				{Module: "example.com/m", Package: "example.com/m/tools", Function: "main"},
+			}},
+			want: "example.com/m/tools",
+		},
+		{
+			name: "import chain",
+			f: &govulncheck.Finding{
+				Trace:       []*govulncheck.Frame{{Module: "golang.org/x/text", Package: "golang.org/x/text/language"}},
+				ImportChain: []string{"golang.org/x/text/language", "example.com/m/internal/lang", "example.com/m/tools"},
+			},
+			want: "example.com/m/tools",
+		},
+		{
+			name: "module",
+			f:    &govulncheck.Finding{Trace: []*govulncheck.Frame{{Module: "golang.org/x/text"}}},
+			want: "",
+		},
+	} {
+		if got := scannedPackage(test.f); got != test.want {
+			t.Errorf("%s: scannedPackage() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
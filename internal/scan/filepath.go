@@ -12,6 +12,10 @@ import (
 // AbsRelShorter takes path and returns its path relative
 // to the current directory, if shorter. Returns path
 // when path is an empty string or upon any error.
+//
+// If the current directory is reached through symbolic links,
+// path can be relative to either the current directory or its
+// resolved form, see resolveDir.
 func AbsRelShorter(path string) string {
 	if path == "" {
 		return ""
@@ -21,15 +25,41 @@ func AbsRelShorter(path string) string {
 	if err != nil {
 		return path
 	}
-	r, err := filepath.Rel(c, path)
-	if err != nil {
-		return path
+	dirs := []string{c}
+	if real, err := filepath.EvalSymlinks(c); err == nil && real != c {
+		dirs = append(dirs, real)
+	}
+
+	shortest := path
+	for _, dir := range dirs {
+		r, err := filepath.Rel(dir, path)
+		if err != nil {
+			continue
+		}
+		rSegments := strings.Split(r, string(filepath.Separator))
+		sSegments := strings.Split(shortest, string(filepath.Separator))
+		if len(rSegments) < len(sSegments) {
+			shortest = r
+		}
 	}
+	return shortest
+}
 
-	rSegments := strings.Split(r, string(filepath.Separator))
-	pathSegments := strings.Split(path, string(filepath.Separator))
-	if len(rSegments) < len(pathSegments) {
-		return r
+// resolveDir returns the absolute path of dir, or of the current
+// directory if dir is empty, with symbolic links resolved.
+// Depending on how it is invoked, the go command reports the files
+// of packages below a symlinked directory under either path, so
+// loading packages from the resolved directory keeps go.mod
+// detection and file positions consistent. It returns dir upon
+// any error.
+func resolveDir(dir string) string {
+	abs, err := filepath.Abs(filepath.FromSlash(dir))
+	if err != nil {
+		return dir
+	}
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return dir
 	}
-	return path
+	return real
 }
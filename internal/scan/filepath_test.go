@@ -5,6 +5,7 @@
 package scan
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -24,3 +25,44 @@ func TestAbsRelShorter(t *testing.T) {
 		}
 	}
 }
+
+func TestSymlinkedDir(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	real := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	if err := os.Mkdir(real, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+
+	if got := resolveDir(link); got != real {
+		t.Errorf("resolveDir(%q) = %q, want %q", link, got, real)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(link); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	// Shells report the unresolved directory in $PWD, which os.Getwd uses.
+	t.Setenv("PWD", link)
+	if got := resolveDir(""); got != real {
+		t.Errorf(`resolveDir("") = %q, want %q`, got, real)
+	}
+	for _, path := range []string{
+		filepath.Join(real, "main.go"),
+		filepath.Join(link, "main.go"),
+	} {
+		if got := AbsRelShorter(path); got != "main.go" {
+			t.Errorf("AbsRelShorter(%q) = %q, want %q", path, got, "main.go")
+		}
+	}
+}
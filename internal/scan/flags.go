@@ -10,26 +10,66 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"text/template"
 
 	"golang.org/x/tools/go/buildutil"
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/semver"
+	"golang.org/x/vuln/internal/web"
 )
 
 type config struct {
 	govulncheck.Config
-	patterns []string
-	db       string
-	dir      string
-	tags     buildutil.TagsFlag
-	test     bool
-	show     ShowFlag
-	format   FormatFlag
-	env      []string
+	patterns    []string
+	db          string
+	dir         string
+	tags        buildutil.TagsFlag
+	test        bool
+	noTests     bool
+	gopath      bool
+	version     bool
+	show        ShowFlag
+	format      FormatFlag
+	failOn      ScanFlag
+	failOnFile  string
+	failOnMods  failLevels
+	allCVEs     bool
+	assumed     versionsFlag
+	listUnreach bool
+	topPerMod   bool
+	groupBy     string
+	explain     bool
+	depth       string
+	allowErrs   bool
+	overrides   string
+	notes       map[string]overrideNote
+	render      string
+	id          string
+	skipMods    string
+	maxDepth    int
+	stacks      int
+	width       int
+	maxResults  int
+	advisoryURL string
+	strictOSV   bool
+	snapshot    string
+	dbRate      float64
+	exportDB    string
+	cacheDir    string
+	cpuProfile  string
+	memProfile  string
+	template    string
+	tmpl        *template.Template
+	listMods    bool
+	env         []string
+	// warnings are reported to the handler, see warnf.
+	warnings []string
 }
 
 func parseFlags(cfg *config, stderr io.Writer, args []string) error {
-	var version bool
 	var json bool
 	var scanFlag ScanFlag
 	var modeFlag ModeFlag
@@ -37,14 +77,54 @@ func parseFlags(cfg *config, stderr io.Writer, args []string) error {
 	flags.SetOutput(stderr)
 	flags.BoolVar(&json, "json", false, "output JSON (Go compatible legacy flag, see format flag)")
 	flags.BoolVar(&cfg.test, "test", false, "analyze test files (only valid for source mode, default false)")
+	flags.BoolVar(&cfg.noTests, "exclude-tests", false, "do not analyze test files, even if -test is set")
+	flags.StringVar(&cfg.depth, "depth", "all", "check 'all' dependencies of the packages for vulnerabilities, or only their 'direct' imports")
+	flags.BoolVar(&cfg.allowErrs, "allow-errors", false, "scan packages with build errors instead of failing, which can make results incomplete (only valid for source mode)")
+	flags.BoolVar(&cfg.gopath, "gopath", false, "analyze packages in GOPATH mode, mapping them to modules best-effort (only valid for source mode, default false)")
 	flags.StringVar(&cfg.dir, "C", "", "change to `dir` before running govulncheck")
 	flags.StringVar(&cfg.db, "db", "https://vuln.go.dev", "vulnerability database `url`")
+	flags.StringVar(&cfg.snapshot, "db-snapshot", "", "use only the vulnerability database snapshot in `dir`, as written by -export-db, instead of -db")
+	flags.Float64Var(&cfg.dbRate, "db-rate", 0, "limit requests to the vulnerability database to `N` per second (default 0, no limit)")
+	flags.StringVar(&cfg.exportDB, "export-db", "", "write the vulnerability database entries consulted by the scan to a snapshot in `dir`")
+	flags.StringVar(&cfg.cacheDir, "cache-dir", "", "reuse the results of module level scans of unchanged go.mod and go.sum files, cached in `dir`")
+	flags.BoolVar(&cfg.strictOSV, "strict-osv", false, "fail on OSV entries with fields unknown to govulncheck, to check database conformance")
 	flags.Var(&modeFlag, "mode", "supports 'source', 'binary', and 'extract' (default 'source')")
 	flags.Var(&cfg.tags, "tags", "comma-separated `list` of build tags")
-	flags.Var(&cfg.show, "show", "enable display of additional information specified by the comma separated `list`\nThe supported values are 'traces','color', 'version', and 'verbose'")
-	flags.Var(&cfg.format, "format", "specify format output\nThe supported values are 'text', 'json', 'sarif', and 'openvex' (default 'text')")
-	flags.BoolVar(&version, "version", false, "print the version information")
+	flags.BoolVar(&cfg.Tools, "tools", false, "scan the build tools imported by the files of the packages with the 'tools' build tag, such as tools.go, instead of the packages (only valid for source mode)")
+	flags.Var(&cfg.show, "show", "enable display of additional information specified by the comma separated `list`\nThe supported values are 'traces', 'panic', 'color', 'version', and 'verbose'")
+	flags.Var(&cfg.format, "format", "specify format output\nThe supported values are 'text', 'json', 'sarif', 'openvex', 'html', and 'sbov' (default 'text')")
+	flags.BoolVar(&cfg.version, "version", false, "print the version information and exit")
 	flags.Var(&scanFlag, "scan", "set the scanning level desired, one of 'module', 'package', or 'symbol' (default 'symbol')")
+	flags.Var(&cfg.failOn, "fail-on", "set the finding level at which govulncheck exits unsuccessfully, one of 'module', 'package', or 'symbol' (default is the scan level)")
+	flags.StringVar(&cfg.failOnFile, "fail-on-modules", "", "read the finding levels at which vulnerabilities fail the scan from `file`, one 'path-prefix level' pair per line for packages of the scanned code, overriding -fail-on")
+	flags.StringVar(&cfg.overrides, "overrides", "", "read module versions considered fixed locally from `file`, one 'module version' pair per line")
+	flags.Var(&cfg.assumed, "assume-version", "match the vulnerabilities of a module against the given version instead of the one used, specified as `module@version` (can be repeated)")
+	flags.StringVar(&cfg.MinGoVersion, "min-go", "", "also report standard library vulnerabilities affecting any Go `version`, such as go1.19, up to the one used for the scan")
+	flags.StringVar(&cfg.skipMods, "skip-modules", "", "do not check the modules listed in `file`, one module path per line, for vulnerabilities")
+	flags.StringVar(&cfg.template, "template", "", "render text output with the Go template in `file` instead of the standard report")
+	flags.BoolVar(&cfg.listMods, "list-modules", false, "print every module in the scan sorted by path, with the vulnerabilities found in it, instead of the standard report")
+	flags.StringVar(&cfg.id, "id", "", "print the vulnerability database entry for `ID`, a Go vulnerability ID or a CVE or GHSA alias, without scanning")
+	flags.StringVar(&cfg.render, "render", "", "render the JSON output of a previous govulncheck run saved in `file`, without scanning")
+	flags.BoolVar(&cfg.EmitGraph, "emit-graph", false, "include the import graph leading to vulnerable packages in JSON output (only valid for source mode)")
+	flags.BoolVar(&cfg.EmitOSV, "emit-osv", false, "include the OSV entries in JSON output exactly as read from the database, with all of their fields")
+	flags.BoolVar(&cfg.EmitEntryPoints, "print-reachable-functions", false, "list all the entry functions from which each called vulnerable symbol is reachable, not just the one in its trace (only valid for source mode)")
+	flags.BoolVar(&cfg.FailFast, "fail-fast", false, "stop at the first called vulnerability and report only it (only valid for source mode)")
+	flags.BoolVar(&cfg.Stream, "stream", false, "analyze the calls of each vulnerability separately and report it as soon as it is found to be called (only valid for source mode)")
+	flags.BoolVar(&cfg.HideGenerated, "hide-generated", false, "do not report vulnerable symbols reachable only through generated code, such as protobuf or gRPC code (only valid for source mode)")
+	flags.BoolVar(&cfg.AsLibrary, "as-library", false, "analyze what importers of the main module can reach, taking the exported functions of its importable packages as entry points instead of main functions (only valid for source mode)")
+	flags.BoolVar(&cfg.EmitTimings, "timings", false, "report the time spent in each phase of the scan, such as loading packages and building the call graph")
+	flags.StringVar(&cfg.cpuProfile, "cpuprofile", "", "write a CPU profile of govulncheck itself to `file`, for use with 'go tool pprof'")
+	flags.StringVar(&cfg.memProfile, "memprofile", "", "write a memory profile of govulncheck itself to `file` at the end of the scan, for use with 'go tool pprof'")
+	flags.BoolVar(&cfg.allCVEs, "all-cves", false, "print a deduplicated list of the CVE and GHSA aliases of all vulnerabilities found")
+	flags.BoolVar(&cfg.listUnreach, "list-unreachable", false, "list the vulnerabilities found in imported packages or required modules that are not called, after the report")
+	flags.BoolVar(&cfg.topPerMod, "top-per-module", false, "print one line per vulnerable module, with its most reachable vulnerability, instead of the full report")
+	flags.StringVar(&cfg.groupBy, "group-by", "", "print the vulnerabilities called from each source file instead of the full report, when set to 'file'")
+	flags.BoolVar(&cfg.explain, "explain", false, "explain for each vulnerability in each module why it is reported at its level, and list the vulnerabilities of every level")
+	flags.IntVar(&cfg.width, "width", 0, "wrap text output to lines of `N` characters (default the terminal width, or 80 if not a terminal)")
+	flags.StringVar(&cfg.advisoryURL, "advisory-url-template", "", "link to more information on each vulnerability with `url`, in which {id} is replaced with the vulnerability ID, instead of the URL given by the database")
+	flags.IntVar(&cfg.stacks, "stacks", 1, "show up to `N` call stacks of each called vulnerable symbol in text output")
+	flags.IntVar(&cfg.maxResults, "max-results", 0, "show at most `N` vulnerabilities, the most reachable first, in text output (default 0, no limit)")
+	flags.IntVar(&cfg.maxDepth, "max-stack-depth", 0, "show at most `N` frames from each end of displayed call stacks (default 0, no limit)")
 
 	// We don't want to print the whole usage message on each flags
 	// error, so we set to a no-op and do the printing ourselves.
@@ -70,9 +150,6 @@ Usage:
 		return errUsage
 	}
 	cfg.patterns = flags.Args()
-	if version {
-		cfg.show = append(cfg.show, "version")
-	}
 	cfg.ScanLevel = govulncheck.ScanLevel(scanFlag)
 	cfg.ScanMode = govulncheck.ScanMode(modeFlag)
 	if err := validateConfig(cfg, json); err != nil {
@@ -83,6 +160,25 @@ Usage:
 }
 
 func validateConfig(cfg *config, json bool) error {
+	// render flag is a shorthand for convert mode reading from a file
+	if cfg.render != "" {
+		if cfg.ScanMode != "" && cfg.ScanMode != govulncheck.ScanModeConvert {
+			return fmt.Errorf("the -render flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if !isFile(cfg.render) {
+			return fmt.Errorf("%q is not a file", cfg.render)
+		}
+		cfg.ScanMode = govulncheck.ScanModeConvert
+	}
+
+	// id flag is a shorthand for query mode looking up one vulnerability
+	if cfg.id != "" {
+		if cfg.ScanMode != "" && cfg.ScanMode != govulncheck.ScanModeQuery {
+			return fmt.Errorf("the -id flag is not supported in %s mode", cfg.ScanMode)
+		}
+		cfg.ScanMode = govulncheck.ScanModeQuery
+	}
+
 	// take care of default values
 	if cfg.ScanMode == "" {
 		cfg.ScanMode = govulncheck.ScanModeSource
@@ -90,6 +186,11 @@ func validateConfig(cfg *config, json bool) error {
 	if cfg.ScanLevel == "" {
 		cfg.ScanLevel = govulncheck.ScanLevelSymbol
 	}
+	// -exclude-tests takes precedence over -test, which
+	// wrappers around govulncheck may add on their own.
+	if cfg.noTests {
+		cfg.test = false
+	}
 	if json {
 		if cfg.format != formatUnset {
 			return fmt.Errorf("the -json flag cannot be used with -format flag")
@@ -105,6 +206,203 @@ func validateConfig(cfg *config, json bool) error {
 	if cfg.format != formatText && len(cfg.show) > 0 {
 		return fmt.Errorf("the -show flag is not supported for %s output", cfg.format)
 	}
+	// the version information is printed as text
+	if cfg.format != formatText && cfg.version {
+		return fmt.Errorf("the -version flag is not supported for %s output", cfg.format)
+	}
+	// traces are call stacks, which only symbol level scans find;
+	// converted output can have them whatever the -scan flag
+	if cfg.ScanLevel != govulncheck.ScanLevelSymbol && scansCode(cfg.ScanMode) {
+		for _, show := range []string{"traces", "panic"} {
+			if slices.Contains(cfg.show, show) {
+				return fmt.Errorf("the -show %s option requires -scan symbol", show)
+			}
+		}
+		if cfg.maxDepth > 0 {
+			return fmt.Errorf("the -max-stack-depth flag requires -scan symbol")
+		}
+	}
+
+	// fail-on flag is only supported with text output, as other
+	// formats always exit successfully
+	if cfg.failOn != "" {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -fail-on flag is not supported for %s output", cfg.format)
+		}
+		if !levelAvailable(cfg.ScanLevel, govulncheck.ScanLevel(cfg.failOn)) {
+			return fmt.Errorf("the -fail-on level %s requires at least -scan %s", cfg.failOn, cfg.failOn)
+		}
+	}
+
+	if cfg.failOnFile != "" {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -fail-on-modules flag is not supported for %s output", cfg.format)
+		}
+		levels, err := readFailLevels(cfg.failOnFile)
+		if err != nil {
+			return err
+		}
+		for prefix, level := range levels {
+			if !levelAvailable(cfg.ScanLevel, level) {
+				return fmt.Errorf("the -fail-on-modules level %s of %s requires at least -scan %s", level, prefix, level)
+			}
+		}
+		cfg.failOnMods = levels
+	}
+
+	// all-cves flag is only supported with text output, other
+	// formats already include aliases in the OSV entries
+	if cfg.format != formatText && cfg.allCVEs {
+		return fmt.Errorf("the -all-cves flag is not supported for %s output", cfg.format)
+	}
+
+	// list-unreachable is only supported with text output, other
+	// formats contain the level of every finding
+	if cfg.listUnreach {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -list-unreachable flag is not supported for %s output", cfg.format)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -list-unreachable flag requires -scan symbol")
+		}
+		if cfg.template != "" || cfg.listMods || cfg.topPerMod || cfg.groupBy != "" {
+			return fmt.Errorf("the -list-unreachable flag cannot be used with the -template, -list-modules, -top-per-module, or -group-by flags")
+		}
+		// With -fail-fast, the calls of most vulnerabilities
+		// are not analyzed.
+		if cfg.FailFast {
+			return fmt.Errorf("the -list-unreachable flag cannot be used with the -fail-fast flag")
+		}
+	}
+
+	if cfg.template != "" {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -template flag is not supported for %s output", cfg.format)
+		}
+		// Parse the template here so that we can catch errors
+		// before outputting the Config.
+		tmpl, err := parseUserTemplate(cfg.template)
+		if err != nil {
+			return err
+		}
+		cfg.tmpl = tmpl
+	}
+
+	if cfg.topPerMod {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -top-per-module flag is not supported for %s output", cfg.format)
+		}
+		if cfg.template != "" || cfg.listMods {
+			return fmt.Errorf("the -top-per-module flag cannot be used with the -template or -list-modules flags")
+		}
+	}
+
+	if cfg.groupBy != "" {
+		if cfg.groupBy != groupFile {
+			return fmt.Errorf("the -group-by flag only supports %q", groupFile)
+		}
+		if cfg.format != formatText {
+			return fmt.Errorf("the -group-by flag is not supported for %s output", cfg.format)
+		}
+		if cfg.template != "" || cfg.listMods || cfg.topPerMod {
+			return fmt.Errorf("the -group-by flag cannot be used with the -template, -list-modules, or -top-per-module flags")
+		}
+		switch cfg.ScanMode {
+		case govulncheck.ScanModeSource, govulncheck.ScanModeConvert:
+		default:
+			return fmt.Errorf("the -group-by flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanMode == govulncheck.ScanModeSource && cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -group-by flag requires -scan symbol")
+		}
+	}
+
+	if cfg.explain {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -explain flag is not supported for %s output", cfg.format)
+		}
+		if cfg.template != "" || cfg.listMods || cfg.topPerMod || cfg.groupBy != "" {
+			return fmt.Errorf("the -explain flag cannot be used with the -template, -list-modules, -top-per-module, or -group-by flags")
+		}
+		switch cfg.ScanMode {
+		case govulncheck.ScanModeSource, govulncheck.ScanModeBinary, govulncheck.ScanModeConvert:
+		default:
+			return fmt.Errorf("the -explain flag is not supported in %s mode", cfg.ScanMode)
+		}
+	}
+
+	if cfg.listMods {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -list-modules flag is not supported for %s output", cfg.format)
+		}
+		if cfg.template != "" {
+			return fmt.Errorf("the -list-modules flag cannot be used with the -template flag")
+		}
+		switch cfg.ScanMode {
+		case govulncheck.ScanModeSource, govulncheck.ScanModeBinary, govulncheck.ScanModeConvert:
+		default:
+			return fmt.Errorf("the -list-modules flag is not supported in %s mode", cfg.ScanMode)
+		}
+	}
+
+	if cfg.stacks < 1 {
+		return fmt.Errorf("the -stacks flag must be at least 1")
+	}
+	if cfg.stacks > 1 {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -stacks flag is not supported for %s output", cfg.format)
+		}
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -stacks flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -stacks flag requires -scan symbol")
+		}
+		cfg.Stacks = cfg.stacks
+	}
+
+	// max-stack-depth only affects how call stacks are displayed,
+	// the stacks in other formats are always complete
+	if cfg.maxDepth < 0 {
+		return fmt.Errorf("the -max-stack-depth flag must not be negative")
+	}
+	if cfg.format != formatText && cfg.maxDepth > 0 {
+		return fmt.Errorf("the -max-stack-depth flag is not supported for %s output", cfg.format)
+	}
+
+	// max-results only affects what is displayed, the
+	// exit code still accounts for all vulnerabilities
+	if cfg.maxResults < 0 {
+		return fmt.Errorf("the -max-results flag must not be negative")
+	}
+	if cfg.maxResults > 0 {
+		if cfg.format != formatText {
+			return fmt.Errorf("the -max-results flag is not supported for %s output", cfg.format)
+		}
+		if cfg.template != "" || cfg.listMods || cfg.topPerMod || cfg.groupBy != "" {
+			return fmt.Errorf("the -max-results flag cannot be used with the -template, -list-modules, -top-per-module, or -group-by flags")
+		}
+	}
+
+	if cfg.dbRate < 0 {
+		return fmt.Errorf("the -db-rate flag must not be negative")
+	}
+
+	if cfg.width < 0 {
+		return fmt.Errorf("the -width flag must not be negative")
+	}
+	if cfg.format != formatText && cfg.width > 0 {
+		return fmt.Errorf("the -width flag is not supported for %s output", cfg.format)
+	}
+
+	if cfg.advisoryURL != "" {
+		if cfg.format != formatText && cfg.format != formatHTML {
+			return fmt.Errorf("the -advisory-url-template flag is not supported for %s output", cfg.format)
+		}
+		if !strings.Contains(cfg.advisoryURL, advisoryIDPlaceholder) {
+			return fmt.Errorf("the -advisory-url-template flag requires a URL containing %s", advisoryIDPlaceholder)
+		}
+	}
 
 	switch cfg.ScanMode {
 	case govulncheck.ScanModeSource:
@@ -124,7 +422,11 @@ func validateConfig(cfg *config, json bool) error {
 		if len(cfg.patterns) != 1 {
 			return fmt.Errorf("only 1 binary can be analyzed at a time")
 		}
-		if !isFile(cfg.patterns[0]) {
+		if archive, _, ok := splitArchivePath(cfg.patterns[0]); ok {
+			if !isFile(archive) {
+				return fmt.Errorf("%q is not a file", archive)
+			}
+		} else if !isFile(cfg.patterns[0]) {
 			return fmt.Errorf("%q is not a file", cfg.patterns[0])
 		}
 	case govulncheck.ScanModeExtract:
@@ -163,6 +465,15 @@ func validateConfig(cfg *config, json bool) error {
 		if len(cfg.tags) > 0 {
 			return fmt.Errorf("the -tags flag is not supported in query mode")
 		}
+		if cfg.id != "" {
+			if len(cfg.patterns) != 0 {
+				return fmt.Errorf("patterns are not accepted with the -id flag")
+			}
+			if cfg.format != formatText && cfg.format != formatJSON {
+				return fmt.Errorf("the -id flag is not supported for %s output", cfg.format)
+			}
+			break
+		}
 		if cfg.format != formatJSON {
 			return fmt.Errorf("the json format must be set in query mode")
 		}
@@ -174,9 +485,220 @@ func validateConfig(cfg *config, json bool) error {
 			}
 		}
 	}
+
+	if cfg.EmitGraph {
+		if cfg.format != formatJSON {
+			return fmt.Errorf("the -emit-graph flag is only supported for json output")
+		}
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -emit-graph flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if !cfg.ScanLevel.WantPackages() {
+			return fmt.Errorf("the -emit-graph flag requires at least -scan package")
+		}
+	}
+
+	if cfg.Tools {
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -tools flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if !cfg.ScanLevel.WantPackages() {
+			return fmt.Errorf("the -tools flag requires at least -scan package")
+		}
+	}
+
+	if cfg.EmitOSV {
+		if cfg.format != formatJSON {
+			return fmt.Errorf("the -emit-osv flag is only supported for json output")
+		}
+		if cfg.ScanMode == govulncheck.ScanModeExtract || cfg.ScanMode == govulncheck.ScanModeConvert {
+			return fmt.Errorf("the -emit-osv flag is not supported in %s mode", cfg.ScanMode)
+		}
+	}
+
+	if cfg.EmitEntryPoints {
+		if cfg.format != formatText && cfg.format != formatJSON {
+			return fmt.Errorf("the -print-reachable-functions flag is not supported for %s output", cfg.format)
+		}
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -print-reachable-functions flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -print-reachable-functions flag requires -scan symbol")
+		}
+	}
+
+	if cfg.FailFast {
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -fail-fast flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -fail-fast flag requires -scan symbol")
+		}
+	}
+
+	if cfg.Stream {
+		if cfg.format != formatText && cfg.format != formatJSON {
+			return fmt.Errorf("the -stream flag is not supported for %s output", cfg.format)
+		}
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -stream flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -stream flag requires -scan symbol")
+		}
+		if cfg.template != "" || cfg.listMods || cfg.topPerMod || cfg.groupBy != "" {
+			return fmt.Errorf("the -stream flag cannot be used with the -template, -list-modules, -top-per-module, or -group-by flags")
+		}
+	}
+
+	if cfg.HideGenerated {
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -hide-generated flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -hide-generated flag requires -scan symbol")
+		}
+	}
+
+	if cfg.AsLibrary {
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -as-library flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if cfg.ScanLevel != govulncheck.ScanLevelSymbol {
+			return fmt.Errorf("the -as-library flag requires -scan symbol")
+		}
+		if cfg.Tools {
+			return fmt.Errorf("the -as-library flag cannot be used with the -tools flag")
+		}
+	}
+
+	if cfg.EmitTimings {
+		if cfg.format != formatText && cfg.format != formatJSON {
+			return fmt.Errorf("the -timings flag is not supported for %s output", cfg.format)
+		}
+		if cfg.ScanMode != govulncheck.ScanModeSource && cfg.ScanMode != govulncheck.ScanModeBinary {
+			return fmt.Errorf("the -timings flag is not supported in %s mode", cfg.ScanMode)
+		}
+	}
+
+	switch cfg.depth {
+	case "", "all":
+	case govulncheck.DepthDirect:
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -depth flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if !cfg.ScanLevel.WantPackages() {
+			return fmt.Errorf("the -depth flag requires at least -scan package")
+		}
+		cfg.Depth = govulncheck.DepthDirect
+	default:
+		return fmt.Errorf("the -depth flag must be 'all' or 'direct'")
+	}
+
+	if cfg.allowErrs && cfg.ScanMode != govulncheck.ScanModeSource {
+		return fmt.Errorf("the -allow-errors flag is not supported in %s mode", cfg.ScanMode)
+	}
+
+	if cfg.gopath {
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -gopath flag is not supported in %s mode", cfg.ScanMode)
+		}
+		// Modules are inferred from the loaded packages.
+		if !cfg.ScanLevel.WantPackages() {
+			return fmt.Errorf("the -gopath flag requires at least -scan package")
+		}
+	}
+
+	if cfg.overrides != "" {
+		if cfg.ScanMode != govulncheck.ScanModeSource && cfg.ScanMode != govulncheck.ScanModeBinary {
+			return fmt.Errorf("the -overrides flag is not supported in %s mode", cfg.ScanMode)
+		}
+		// Read the overrides here so that we can catch errors
+		// before outputting the Config.
+		overrides, notes, err := readOverrides(cfg.overrides)
+		if err != nil {
+			return err
+		}
+		cfg.Overrides = overrides
+		cfg.notes = notes
+	}
+
+	if len(cfg.assumed) > 0 {
+		if cfg.ScanMode != govulncheck.ScanModeSource && cfg.ScanMode != govulncheck.ScanModeBinary {
+			return fmt.Errorf("the -assume-version flag is not supported in %s mode", cfg.ScanMode)
+		}
+		cfg.AssumedVersions = cfg.assumed
+	}
+
+	if cfg.MinGoVersion != "" {
+		if cfg.ScanMode != govulncheck.ScanModeSource && cfg.ScanMode != govulncheck.ScanModeBinary {
+			return fmt.Errorf("the -min-go flag is not supported in %s mode", cfg.ScanMode)
+		}
+		if semver.GoTagToSemver(cfg.MinGoVersion) == "" {
+			return fmt.Errorf("the -min-go flag must be a Go version such as go1.19, not %q", cfg.MinGoVersion)
+		}
+	}
+
+	if cfg.snapshot != "" {
+		dir, err := filepath.Abs(cfg.snapshot)
+		if err != nil {
+			return err
+		}
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			return fmt.Errorf("%q is not a directory", cfg.snapshot)
+		}
+		u, err := web.URLFromFilePath(dir)
+		if err != nil {
+			return err
+		}
+		cfg.db = u.String()
+	}
+
+	if cfg.exportDB != "" {
+		switch cfg.ScanMode {
+		case govulncheck.ScanModeSource, govulncheck.ScanModeBinary, govulncheck.ScanModeQuery:
+		default:
+			return fmt.Errorf("the -export-db flag is not supported in %s mode", cfg.ScanMode)
+		}
+	}
+
+	if cfg.cacheDir != "" {
+		if cfg.ScanMode != govulncheck.ScanModeSource {
+			return fmt.Errorf("the -cache-dir flag is not supported in %s mode", cfg.ScanMode)
+		}
+		// Package and symbol findings also depend on
+		// the code being scanned, not only on go.sum.
+		if cfg.ScanLevel != govulncheck.ScanLevelModule {
+			return fmt.Errorf("the -cache-dir flag requires -scan module")
+		}
+	}
+
+	if cfg.skipMods != "" {
+		if cfg.ScanMode != govulncheck.ScanModeSource && cfg.ScanMode != govulncheck.ScanModeBinary {
+			return fmt.Errorf("the -skip-modules flag is not supported in %s mode", cfg.ScanMode)
+		}
+		skip, err := readSkipModules(cfg.skipMods)
+		if err != nil {
+			return err
+		}
+		cfg.SkipModules = skip
+	}
 	return nil
 }
 
+// levelAvailable reports whether findings at level can
+// be produced by a scan at scanLevel.
+func levelAvailable(scanLevel, level govulncheck.ScanLevel) bool {
+	switch level {
+	case govulncheck.ScanLevelSymbol:
+		return scanLevel.WantSymbols()
+	case govulncheck.ScanLevelPackage:
+		return scanLevel.WantPackages()
+	}
+	return true
+}
+
 func isFile(path string) bool {
 	s, err := os.Stat(path)
 	if err != nil {
@@ -185,6 +707,16 @@ func isFile(path string) bool {
 	return !s.IsDir()
 }
 
+// groupFile is the -group-by value that groups
+// the vulnerabilities called by source file.
+const groupFile = "file"
+
+// scansCode reports whether mode scans code at
+// the level set by the -scan flag.
+func scansCode(mode govulncheck.ScanMode) bool {
+	return mode == govulncheck.ScanModeSource || mode == govulncheck.ScanModeBinary
+}
+
 var errFlagParse = errors.New("see -help for details")
 
 // ShowFlag is used for parsing and validation of
@@ -193,6 +725,7 @@ type ShowFlag []string
 
 var supportedShows = map[string]bool{
 	"traces":  true,
+	"panic":   true,
 	"color":   true,
 	"verbose": true,
 	"version": true,
@@ -221,6 +754,9 @@ func (v ShowFlag) Update(h *TextHandler) {
 		switch show {
 		case "traces":
 			h.showTraces = true
+		case "panic":
+			h.showTraces = true
+			h.showPanic = true
 		case "color":
 			h.showColor = true
 		case "version":
@@ -241,6 +777,8 @@ const (
 	formatText    = "text"
 	formatSarif   = "sarif"
 	formatOpenVEX = "openvex"
+	formatHTML    = "html"
+	formatSBOV    = "sbov"
 )
 
 var supportedFormats = map[string]bool{
@@ -248,6 +786,8 @@ var supportedFormats = map[string]bool{
 	formatText:    true,
 	formatSarif:   true,
 	formatOpenVEX: true,
+	formatHTML:    true,
+	formatSBOV:    true,
 }
 
 func (f *FormatFlag) Get() interface{} { return *f }
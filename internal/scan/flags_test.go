@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestExcludeTests(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"-test"}, true},
+		{[]string{"-exclude-tests"}, false},
+		{[]string{"-test", "-exclude-tests"}, false},
+		{[]string{"-exclude-tests", "-test"}, false},
+	} {
+		cfg := &config{}
+		if err := parseFlags(cfg, io.Discard, append(test.args, "./...")); err != nil {
+			t.Fatalf("parseFlags(%q): %v", test.args, err)
+		}
+		if cfg.test != test.want {
+			t.Errorf("parseFlags(%q): test = %t, want %t", test.args, cfg.test, test.want)
+		}
+	}
+}
+
+func TestVersionFormat(t *testing.T) {
+	for _, test := range []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"-version"}, false},
+		{[]string{"-version", "-format", "text"}, false},
+		{[]string{"-version", "-format", "json"}, true},
+		{[]string{"-version", "-json"}, true},
+		{[]string{"-version", "-format", "sarif"}, true},
+	} {
+		cfg := &config{}
+		if err := parseFlags(cfg, io.Discard, test.args); (err != nil) != test.wantErr {
+			t.Errorf("parseFlags(%q): got error %v, want error: %t", test.args, err, test.wantErr)
+		}
+	}
+}
+
+func TestDBRate(t *testing.T) {
+	for _, test := range []struct {
+		args    []string
+		wantErr bool
+		want    rate.Limit // 0 for no limiter
+	}{
+		{nil, false, 0},
+		{[]string{"-db-rate", "2.5"}, false, 2.5},
+		{[]string{"-db-rate", "-1"}, true, 0},
+	} {
+		cfg := &config{}
+		err := parseFlags(cfg, io.Discard, append(test.args, "./..."))
+		if (err != nil) != test.wantErr {
+			t.Fatalf("parseFlags(%q): got error %v, want error: %t", test.args, err, test.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		var got rate.Limit
+		if l := dbLimiter(cfg); l != nil {
+			got = l.Limit()
+		}
+		if got != test.want {
+			t.Errorf("parseFlags(%q): limit = %v, want %v", test.args, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal/semver"
+)
+
+// gopathResolver maps packages loaded in GOPATH mode, which have
+// no module information, to the modules providing them.
+type gopathResolver struct {
+	// isModule reports whether path is the path of a module.
+	isModule func(path string) bool
+	// version returns the version of the module checked out in
+	// dir, or "" if it is not known.
+	version func(dir string) string
+
+	modules    map[string]*packages.Module // resolved modules by path
+	notModules map[string]bool             // paths that are not modules
+}
+
+func newGOPATHResolver(env []string) *gopathResolver {
+	return &gopathResolver{
+		isModule:   func(path string) bool { return proxyHasModule(env, path) },
+		version:    vcsVersion,
+		modules:    make(map[string]*packages.Module),
+		notModules: make(map[string]bool),
+	}
+}
+
+// resolve returns the module of pkg, or nil if it cannot be found.
+// The module path is the longest prefix of the package path that is
+// a module. Its version is taken from the version control checkout
+// of the module in GOPATH, and is empty if that is not possible.
+func (r *gopathResolver) resolve(pkg *packages.Package) *packages.Module {
+	elems := strings.Split(pkg.PkgPath, "/")
+	// Module paths start with a domain name.
+	if !strings.Contains(elems[0], ".") {
+		return nil
+	}
+	for i := len(elems); i > 0; i-- {
+		path := strings.Join(elems[:i], "/")
+		if m, ok := r.modules[path]; ok {
+			return m
+		}
+		if r.notModules[path] {
+			continue
+		}
+		if !r.isModule(path) {
+			r.notModules[path] = true
+			continue
+		}
+		m := &packages.Module{Path: path}
+		if pkg.Dir != "" {
+			// The module root is the package directory
+			// without the package path relative to the module.
+			dir := pkg.Dir
+			for range elems[i:] {
+				dir = filepath.Dir(dir)
+			}
+			m.Dir = dir
+			m.Version = r.version(dir)
+		}
+		r.modules[path] = m
+		return m
+	}
+	return nil
+}
+
+// proxyHasModule reports whether path is a module known to the
+// module proxy, by asking the go command in module mode.
+func proxyHasModule(env []string, path string) bool {
+	cmd := exec.Command("go", "list", "-m", "-json", path+"@latest")
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(slices.Clip(env), "GO111MODULE=on", "GOFLAGS=-mod=mod")
+	cmd.Dir = os.TempDir() // outside of any module
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	var m struct{ Path string }
+	return json.Unmarshal(out, &m) == nil && m.Path == path
+}
+
+// vcsVersion returns the semantic version tag of the git checkout
+// in dir, if its current commit is tagged with one.
+func vcsVersion(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "tag", "--points-at", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	var version string
+	for _, tag := range strings.Fields(string(out)) {
+		if strings.HasPrefix(tag, "v") && semver.Valid(tag) &&
+			(version == "" || semver.Less(version, tag)) {
+			version = tag
+		}
+	}
+	return version
+}
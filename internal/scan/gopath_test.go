@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGOPATHResolver(t *testing.T) {
+	gopath := filepath.FromSlash("/gopath/src")
+	modules := map[string]bool{
+		"golang.org/x/text":   true,
+		"github.com/a/b":      true,
+		"github.com/a/b/v2/c": true,
+	}
+	var lookups []string
+	r := &gopathResolver{
+		isModule: func(path string) bool {
+			lookups = append(lookups, path)
+			return modules[path]
+		},
+		version: func(dir string) string {
+			if dir == filepath.Join(gopath, "golang.org", "x", "text") {
+				return "v0.3.0"
+			}
+			return ""
+		},
+		modules:    make(map[string]*packages.Module),
+		notModules: make(map[string]bool),
+	}
+	pkg := func(path string) *packages.Package {
+		return &packages.Package{PkgPath: path, Dir: filepath.Join(gopath, filepath.FromSlash(path))}
+	}
+
+	for _, test := range []struct {
+		pkg  string
+		want *packages.Module
+	}{
+		{"golang.org/x/text/language", &packages.Module{Path: "golang.org/x/text", Version: "v0.3.0", Dir: filepath.Join(gopath, "golang.org", "x", "text")}},
+		{"golang.org/x/text/internal/tag", &packages.Module{Path: "golang.org/x/text", Version: "v0.3.0", Dir: filepath.Join(gopath, "golang.org", "x", "text")}},
+		{"github.com/a/b/v2/c/d", &packages.Module{Path: "github.com/a/b/v2/c", Dir: filepath.Join(gopath, "github.com", "a", "b", "v2", "c")}},
+		{"example.com/private/app", nil},
+		{"myapp/internal/x", nil},
+	} {
+		if got := r.resolve(pkg(test.pkg)); !cmp.Equal(got, test.want) {
+			t.Errorf("resolve(%s) = %+v, want %+v", test.pkg, got, test.want)
+		}
+	}
+
+	// Paths are looked up at most once, and not at all
+	// for paths that do not start with a domain name.
+	wantLookups := []string{
+		"golang.org/x/text/language", "golang.org/x/text",
+		"golang.org/x/text/internal/tag", "golang.org/x/text/internal",
+		"github.com/a/b/v2/c/d", "github.com/a/b/v2/c",
+		"example.com/private/app", "example.com/private", "example.com",
+	}
+	if diff := cmp.Diff(wantLookups, lookups); diff != "" {
+		t.Errorf("lookups mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"html/template"
+	"io"
+	"sort"
+
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// HTMLHandler writes govulncheck output as a self-contained HTML
+// report, see -format html.
+type HTMLHandler struct {
+	w        io.Writer
+	config   *govulncheck.Config
+	osvs     []*osv.Entry
+	findings []*findingSummary
+
+	// advisoryURLTemplate is the template of the links
+	// of vulnerabilities, see -advisory-url-template.
+	advisoryURLTemplate string
+}
+
+// NewHTMLHandler returns a handler that writes govulncheck
+// output as an HTML report.
+func NewHTMLHandler(w io.Writer) *HTMLHandler {
+	return &HTMLHandler{w: w}
+}
+
+// htmlReport is the value the HTML report template is executed with.
+type htmlReport struct {
+	Config *govulncheck.Config
+	Vulns  []*htmlVuln
+}
+
+// htmlVuln describes a vulnerability of the report.
+type htmlVuln struct {
+	ID      string
+	Summary string
+	URL     string
+	Aliases []string
+	// Level is how the vulnerability was found:
+	// called, imported, or required.
+	Level   string
+	Modules []htmlModule
+	Traces  []htmlTrace
+}
+
+// htmlModule is a module affected by a vulnerability.
+type htmlModule struct {
+	Path  string
+	Found string
+	Fixed string
+}
+
+// htmlTrace is a call stack of a vulnerable symbol, from
+// the entry function to the symbol.
+type htmlTrace struct {
+	Symbol string
+	Frames []string
+}
+
+func (h *HTMLHandler) Config(c *govulncheck.Config) error {
+	h.config = c
+	return nil
+}
+
+func (h *HTMLHandler) SBOM(s *govulncheck.SBOM) error {
+	return nil // not needed by the report
+}
+
+func (h *HTMLHandler) Progress(p *govulncheck.Progress) error {
+	if p.SymbolsFailed && h.config != nil {
+		// Report the scan level the findings are at.
+		lowered := *h.config
+		lowered.ScanLevel = govulncheck.ScanLevelPackage
+		h.config = &lowered
+	}
+	return nil // other progress is not needed by the report
+}
+
+func (h *HTMLHandler) Graph(g *govulncheck.Graph) error {
+	return nil // not needed by the report
+}
+
+func (h *HTMLHandler) Timing(t *govulncheck.Timing) error {
+	return nil // not needed by the report
+}
+
+func (h *HTMLHandler) OSV(e *osv.Entry) error {
+	h.osvs = append(h.osvs, e)
+	return nil
+}
+
+func (h *HTMLHandler) Finding(f *govulncheck.Finding) error {
+	if err := validateFindings(f); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(f))
+	return nil
+}
+
+// Flush writes the report. Like the other machine readable
+// formats, it does not report vulnerabilities with an error.
+func (h *HTMLHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	report := &htmlReport{Config: h.config}
+	for _, findings := range groupByVuln(h.findings) {
+		report.Vulns = append(report.Vulns, newHTMLVuln(findings, h.advisoryURLTemplate))
+	}
+	// Show the most reachable vulnerabilities first.
+	sort.SliceStable(report.Vulns, func(i, j int) bool {
+		return levelRank[report.Vulns[i].Level] < levelRank[report.Vulns[j].Level]
+	})
+	return htmlTemplate.Execute(h.w, report)
+}
+
+var levelRank = map[string]int{"called": 0, "imported": 1, "required": 2}
+
+// newHTMLVuln describes the vulnerability of findings, linking
+// to it as advisoryURL does with template.
+func newHTMLVuln(findings []*findingSummary, template string) *htmlVuln {
+	e := findings[0].OSV
+	v := &htmlVuln{
+		ID:      e.ID,
+		Summary: e.Summary,
+		URL:     advisoryURL(template, e),
+		Aliases: e.Aliases,
+		Level:   findingLevel(findings),
+	}
+	if v.Summary == "" {
+		v.Summary = e.Details
+	}
+	for _, module := range groupByModule(findings) {
+		frame := module[0].Trace[0]
+		path := frame.Module
+		if frame.Module == internal.GoStdModulePath && frame.Package != "" {
+			path = frame.Package
+		}
+		fixed := moduleVersionString(frame.Module, module[0].FixedVersion)
+		if fixed == "" {
+			fixed = "N/A"
+		}
+		v.Modules = append(v.Modules, htmlModule{
+			Path:  path,
+			Found: moduleVersionString(frame.Module, frame.Version),
+			Fixed: fixed,
+		})
+	}
+	for _, f := range findings {
+		if f.Compact == "" {
+			continue
+		}
+		t := htmlTrace{Symbol: symbol(f.Trace[0], false)}
+		for i := len(f.Trace) - 1; i >= 0; i-- {
+			frame := symbolName(f.Trace[i])
+			if f.Trace[i].Position != nil {
+				frame += " @ " + symbolPath(f.Trace[i])
+			}
+			t.Frames = append(t.Frames, frame)
+		}
+		v.Traces = append(v.Traces, t)
+	}
+	sort.SliceStable(v.Traces, func(i, j int) bool {
+		return v.Traces[i].Symbol < v.Traces[j].Symbol
+	})
+	return v
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Govulncheck report</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; color: #202224; }
+h1 { font-size: 1.5em; }
+dl.config { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }
+dl.config dt { font-weight: bold; }
+dl.config dd { margin: 0; }
+details { border: 1px solid #dadce0; border-radius: 4px; margin: 0.5em 0; padding: 0.5em 1em; }
+summary { cursor: pointer; font-weight: bold; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #dadce0; padding: 0.2em 0.6em; text-align: left; }
+pre { background: #f8f9fa; padding: 0.5em; overflow-x: auto; }
+.badge { border-radius: 3px; color: #fff; font-size: 0.8em; padding: 0.1em 0.4em; }
+.called { background: #c5221f; }
+.imported { background: #e37400; }
+.required { background: #5f6368; }
+</style>
+</head>
+<body>
+<h1>Govulncheck report</h1>
+{{with .Config}}<dl class="config">
+{{with .ScanMode}}<dt>Scan mode</dt><dd>{{.}}</dd>
+{{end}}{{with .ScanLevel}}<dt>Scan level</dt><dd>{{.}}</dd>
+{{end}}{{with .GoVersion}}<dt>Go version</dt><dd>{{.}}</dd>
+{{end}}{{with .DB}}<dt>Database</dt><dd>{{.}}</dd>
+{{end}}</dl>
+{{end}}{{if not .Vulns}}<p>No vulnerabilities found.</p>
+{{else}}<p>{{len .Vulns}} {{if eq (len .Vulns) 1}}vulnerability{{else}}vulnerabilities{{end}} found.</p>
+{{range .Vulns}}<details>
+<summary><span class="badge {{.Level}}">{{.Level}}</span> {{.ID}}</summary>
+<p>{{.Summary}}</p>
+<p>More info: <a href="{{.URL}}">{{.URL}}</a></p>
+{{with .Aliases}}<p>Aliases: {{range $i, $a := .}}{{if $i}}, {{end}}{{$a}}{{end}}</p>
+{{end}}<table>
+<tr><th>Module</th><th>Found in</th><th>Fixed in</th></tr>
+{{range .Modules}}<tr><td>{{.Path}}</td><td>{{.Found}}</td><td>{{.Fixed}}</td></tr>
+{{end}}</table>
+{{range .Traces}}<details>
+<summary>{{.Symbol}}</summary>
+<pre>{{range .Frames}}{{.}}
+{{end}}</pre>
+</details>
+{{end}}</details>
+{{end}}{{end}}</body>
+</html>
+`))
@@ -0,0 +1,45 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestHTMLAdvisoryURL(t *testing.T) {
+	entry := &osv.Entry{ID: "GO-2021-0113", DatabaseSpecific: &osv.DatabaseSpecific{URL: "https://db.example.com/GO-2021-0113"}}
+	for _, test := range []struct {
+		template string
+		want     string
+	}{
+		{"", `href="https://db.example.com/GO-2021-0113"`},
+		{"https://sec.example.com/{id}", `href="https://sec.example.com/GO-2021-0113"`},
+	} {
+		var buf bytes.Buffer
+		h := NewHTMLHandler(&buf)
+		h.advisoryURLTemplate = test.template
+		if err := h.Config(&govulncheck.Config{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.OSV(entry); err != nil {
+			t.Fatal(err)
+		}
+		f := &govulncheck.Finding{OSV: entry.ID, Trace: []*govulncheck.Frame{{Module: "golang.org/x/text", Version: "v0.3.5"}}}
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), test.want) {
+			t.Errorf("report with template %q does not contain %s:\n%s", test.template, test.want, buf.String())
+		}
+	}
+}
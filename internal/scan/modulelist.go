@@ -0,0 +1,203 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// ModuleListHandler prints an inventory of the modules in the scan,
+// annotated with the vulnerabilities found in them, see -list-modules.
+type ModuleListHandler struct {
+	w        io.Writer
+	sbom     *govulncheck.SBOM
+	findings []*findingSummary
+
+	// buildList holds the modules of the build list of a source
+	// scan, which includes modules providing no analyzed package.
+	buildList []*govulncheck.Module
+
+	// failOn is the finding level at which vulnerabilities
+	// are reported as found. Defaults to the scan level.
+	failOn    govulncheck.ScanLevel
+	scanLevel govulncheck.ScanLevel
+
+	// failOnModules overrides failOn for
+	// some packages of the scanned code, see -fail-on-modules.
+	failOnModules failLevels
+}
+
+// NewModuleListHandler returns a handler that writes the module
+// inventory of the scan to w.
+func NewModuleListHandler(w io.Writer) *ModuleListHandler {
+	return &ModuleListHandler{w: w}
+}
+
+func (h *ModuleListHandler) Config(c *govulncheck.Config) error {
+	h.scanLevel = c.ScanLevel
+	h.failOnModules = h.failOnModules.resolve(c.MainModules)
+	return nil
+}
+
+func (h *ModuleListHandler) SBOM(s *govulncheck.SBOM) error {
+	h.sbom = s
+	return nil
+}
+
+func (h *ModuleListHandler) Progress(p *govulncheck.Progress) error {
+	if p.SymbolsFailed {
+		h.scanLevel = withoutSymbols(h.scanLevel)
+		h.failOn = withoutSymbols(h.failOn)
+		h.failOnModules = h.failOnModules.withoutSymbols()
+	}
+	return nil // not part of the inventory
+}
+
+func (h *ModuleListHandler) Graph(g *govulncheck.Graph) error {
+	return nil // not part of the inventory
+}
+
+func (h *ModuleListHandler) Timing(t *govulncheck.Timing) error {
+	return nil // not part of the inventory
+}
+
+func (h *ModuleListHandler) OSV(e *osv.Entry) error {
+	return nil // only the IDs of the findings are listed
+}
+
+func (h *ModuleListHandler) Finding(f *govulncheck.Finding) error {
+	if err := validateFindings(f); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(f))
+	return nil
+}
+
+// Flush prints each module of the scan on its own line, sorted by
+// path, followed by the vulnerabilities found in it and the level
+// at which they were found. Like text output, it reports
+// vulnerabilities found at the -fail-on level with an error.
+func (h *ModuleListHandler) Flush() error {
+	type modVersion struct{ path, version string }
+	var mods []modVersion
+	for _, m := range h.buildList {
+		mods = append(mods, modVersion{m.Path, m.Version})
+	}
+	if h.sbom != nil {
+		// The SBOM also lists the standard library.
+		for _, m := range h.sbom.Modules {
+			mods = append(mods, modVersion{m.Path, m.Version})
+		}
+	}
+	// Findings name the module versions actually used,
+	// which the SBOM may not list, for instance in binaries.
+	vulns := make(map[modVersion][]*findingSummary)
+	for _, f := range h.findings {
+		mv := modVersion{f.Trace[0].Module, f.Trace[0].Version}
+		if _, ok := vulns[mv]; !ok && !slices.Contains(mods, mv) {
+			mods = append(mods, mv)
+		}
+		vulns[mv] = append(vulns[mv], f)
+	}
+	slices.SortFunc(mods, func(a, b modVersion) int {
+		return compareModules(a.path, a.version, b.path, b.version)
+	})
+	mods = slices.Compact(mods)
+
+	for _, m := range mods {
+		line := m.path
+		if m.version != "" {
+			line += "@" + m.version
+		}
+		byID := groupBy(vulns[m], func(left, right *findingSummary) int {
+			return strings.Compare(left.Finding.OSV, right.Finding.OSV)
+		})
+		var ids []string
+		for _, group := range byID {
+			ids = append(ids, fmt.Sprintf("%s (%s)", group[0].Finding.OSV, findingLevel(group)))
+		}
+		if len(ids) > 0 {
+			line += " " + strings.Join(ids, ", ")
+		}
+		if _, err := fmt.Fprintln(h.w, line); err != nil {
+			return err
+		}
+	}
+
+	level := h.failOn
+	if level == "" {
+		level = h.scanLevel
+	}
+	if vulnerabilitiesFound(h.findings, level, h.failOnModules) {
+		return errVulnerabilitiesFound
+	}
+	return nil
+}
+
+// findingLevel describes the most precise level of the findings,
+// which are all for the same vulnerability.
+func findingLevel(findings []*findingSummary) string {
+	switch {
+	case isCalled(findings):
+		return "called"
+	case isImported(findings):
+		return "imported"
+	default:
+		return "required"
+	}
+}
+
+// buildList returns the modules of the build list of the main
+// modules in dir, as listed by 'go list -m all'. Like the package
+// graph, it includes both replaced modules and their replacements.
+func buildList(dir string, env []string) ([]*govulncheck.Module, error) {
+	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd.Dir = dir
+	cmd.Env = env
+	out, err := cmd.Output()
+	if err != nil {
+		var eerr *exec.ExitError
+		if errors.As(err, &eerr) && len(eerr.Stderr) > 0 {
+			err = errors.New(strings.TrimSpace(string(eerr.Stderr)))
+		}
+		return nil, fmt.Errorf("listing modules: %w", err)
+	}
+	return parseBuildList(out)
+}
+
+// parseBuildList parses the output of 'go list -m -json', which is
+// a stream of JSON module objects.
+func parseBuildList(data []byte) ([]*govulncheck.Module, error) {
+	type module struct {
+		Path    string
+		Version string
+		Replace *module
+	}
+	var mods []*govulncheck.Module
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var m module
+		if err := dec.Decode(&m); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("listing modules: %w", err)
+		}
+		mods = append(mods, &govulncheck.Module{Path: m.Path, Version: m.Version})
+		if r := m.Replace; r != nil {
+			mods = append(mods, &govulncheck.Module{Path: r.Path, Version: r.Version})
+		}
+	}
+	return mods, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+func TestModuleList(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewModuleListHandler(&buf)
+	// The build list includes modules providing no package,
+	// which the SBOM of a source scan does not list.
+	h.buildList = []*govulncheck.Module{
+		{Path: "golang.org/main"},
+		{Path: "golang.org/a", Version: "v1.10.0"},
+		{Path: "golang.org/unused", Version: "v0.1.0"},
+	}
+	if err := h.Config(&govulncheck.Config{ScanLevel: govulncheck.ScanLevelSymbol}); err != nil {
+		t.Fatal(err)
+	}
+	sbom := &govulncheck.SBOM{Modules: []*govulncheck.Module{
+		{Path: "stdlib", Version: "v1.21.0"},
+		{Path: "golang.org/main"},
+		{Path: "golang.org/a", Version: "v1.10.0"},
+	}}
+	if err := h.SBOM(sbom); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []*govulncheck.Finding{
+		{OSV: "GO-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/a", Version: "v1.10.0", Package: "golang.org/a/p", Function: "F"}}},
+		{OSV: "GO-0002", Trace: []*govulncheck.Frame{{Module: "golang.org/a", Version: "v1.9.0", Package: "golang.org/a/p"}}},
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Flush(); !errors.Is(err, errVulnerabilitiesFound) {
+		t.Errorf("Flush() = %v, want %v", err, errVulnerabilitiesFound)
+	}
+	want := `golang.org/a@v1.9.0 GO-0002 (imported)
+golang.org/a@v1.10.0 GO-0001 (called)
+golang.org/main
+golang.org/unused@v0.1.0
+stdlib@v1.21.0
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestParseBuildList(t *testing.T) {
+	data := []byte(`{
+	"Path": "golang.org/main",
+	"Main": true
+}
+{
+	"Path": "golang.org/a",
+	"Version": "v1.0.0",
+	"Replace": {
+		"Path": "golang.org/afork",
+		"Version": "v1.0.1"
+	}
+}
+{
+	"Path": "golang.org/b",
+	"Version": "v0.2.0"
+}
+`)
+	got, err := parseBuildList(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*govulncheck.Module{
+		{Path: "golang.org/main"},
+		{Path: "golang.org/a", Version: "v1.0.0"},
+		{Path: "golang.org/afork", Version: "v1.0.1"},
+		{Path: "golang.org/b", Version: "v0.2.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/vuln/internal/semver"
+)
+
+// readOverrides reads the overrides file at path. Each non-empty
+// line of the file has the form
+//
+//	module/path version # reason
+//
+// and states that versions of module/path at or above version are
+// fixed locally. The reason, along with the # before it, is optional.
+// It can give an expiry date of the override, "until 2024-06" or
+// "until 2024-06-30", after which the override should be revisited.
+// Lines starting with # are comments.
+//
+// The reasons are returned by module path.
+func readOverrides(path string) (map[string]string, map[string]overrideNote, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseOverrides(path, data)
+}
+
+// overrideNote is the reason given for an override.
+type overrideNote struct {
+	// Pos is the file:line position of the override.
+	Pos string
+	// Reason is the text of the reason.
+	Reason string
+	// Until is the expiry date in the reason, if any,
+	// as written, and Expires is the time it ends.
+	Until   string
+	Expires time.Time
+}
+
+// expired reports whether the override expired by time now.
+func (n overrideNote) expired(now time.Time) bool {
+	return !n.Expires.IsZero() && !now.Before(n.Expires)
+}
+
+// warnExpired records a warning in cfg for each override whose
+// reason expired by time now. Expired overrides still apply, the
+// warnings are meant to get them revisited.
+func warnExpired(cfg *config, now time.Time) {
+	var expired []string
+	for mod, n := range cfg.notes {
+		if n.expired(now) {
+			expired = append(expired, mod)
+		}
+	}
+	sort.Strings(expired)
+	for _, mod := range expired {
+		n := cfg.notes[mod]
+		cfg.warnf("%s: the override of %s expired at the end of %s, revisit it: %s", n.Pos, mod, n.Until, n.Reason)
+	}
+}
+
+// untilRegexp matches the expiry date of an override reason.
+var untilRegexp = regexp.MustCompile(`\buntil (\d{4}-\d{2}(-\d{2})?)\b`)
+
+func parseOverrides(path string, data []byte) (map[string]string, map[string]overrideNote, error) {
+	overrides := make(map[string]string)
+	notes := make(map[string]overrideNote)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line, reason, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("%s:%d: want \"module version\", got %q", path, n, line)
+		}
+		mod, version := fields[0], fields[1]
+		if !strings.HasPrefix(version, "v") || !semver.Valid(version) {
+			return nil, nil, fmt.Errorf("%s:%d: invalid version %q for module %s", path, n, version, mod)
+		}
+		if _, ok := overrides[mod]; ok {
+			return nil, nil, fmt.Errorf("%s:%d: duplicate override for module %s", path, n, mod)
+		}
+		overrides[mod] = version
+		if reason = strings.TrimSpace(reason); reason == "" {
+			continue
+		}
+		note := overrideNote{Pos: fmt.Sprintf("%s:%d", path, n), Reason: reason}
+		if m := untilRegexp.FindStringSubmatch(reason); m != nil {
+			note.Until = m[1]
+			var err error
+			if m[2] == "" {
+				// The override holds for the whole month.
+				note.Expires, err = time.Parse("2006-01", m[1])
+				note.Expires = note.Expires.AddDate(0, 1, 0)
+			} else {
+				note.Expires, err = time.Parse("2006-01-02", m[1])
+				note.Expires = note.Expires.AddDate(0, 0, 1)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: invalid expiry date %q for module %s", path, n, m[1], mod)
+			}
+		}
+		notes[mod] = note
+	}
+	return overrides, notes, scanner.Err()
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOverrides(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		in        string
+		want      map[string]string
+		wantNotes map[string]overrideNote
+		wantErr   bool
+	}{
+		{
+			name: "valid",
+			in: `# patched internally
+golang.org/x/text v0.3.0
+
+github.com/tidwall/gjson   v1.6.5-patched.1
+stdlib v1.21.0
+`,
+			want: map[string]string{
+				"golang.org/x/text":        "v0.3.0",
+				"github.com/tidwall/gjson": "v1.6.5-patched.1",
+				"stdlib":                   "v1.21.0",
+			},
+			wantNotes: map[string]overrideNote{},
+		},
+		{
+			name: "reasons",
+			in: `golang.org/x/text v0.3.0 # backported fix, ticket SEC-1
+github.com/tidwall/gjson v1.6.5 # accepted until 2024-06, ticket SEC-123
+stdlib v1.21.0 #until 2024-06-30
+`,
+			want: map[string]string{
+				"golang.org/x/text":        "v0.3.0",
+				"github.com/tidwall/gjson": "v1.6.5",
+				"stdlib":                   "v1.21.0",
+			},
+			wantNotes: map[string]overrideNote{
+				"golang.org/x/text": {
+					Pos:    "overrides.txt:1",
+					Reason: "backported fix, ticket SEC-1",
+				},
+				"github.com/tidwall/gjson": {
+					Pos:     "overrides.txt:2",
+					Reason:  "accepted until 2024-06, ticket SEC-123",
+					Until:   "2024-06",
+					Expires: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+				},
+				"stdlib": {
+					Pos:     "overrides.txt:3",
+					Reason:  "until 2024-06-30",
+					Until:   "2024-06-30",
+					Expires: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:      "empty",
+			in:        "",
+			want:      map[string]string{},
+			wantNotes: map[string]overrideNote{},
+		},
+		{
+			name:    "invalid expiry",
+			in:      "golang.org/x/text v0.3.0 # until 2024-13\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing version",
+			in:      "golang.org/x/text\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			in:      "golang.org/x/text 0.3.0\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate",
+			in:      "golang.org/x/text v0.3.0\ngolang.org/x/text v0.3.1\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotNotes, err := parseOverrides("overrides.txt", []byte(test.in))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); !test.wantErr && diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantNotes, gotNotes); !test.wantErr && diff != "" {
+				t.Errorf("notes mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOverrideExpired(t *testing.T) {
+	n := overrideNote{Until: "2024-06", Expires: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+	for _, test := range []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	} {
+		if got := n.expired(test.now); got != test.want {
+			t.Errorf("expired(%v) = %t, want %t", test.now, got, test.want)
+		}
+	}
+	if (overrideNote{}).expired(time.Now()) {
+		t.Error("override without expiry date expired")
+	}
+}
+
+func TestWarnExpired(t *testing.T) {
+	cfg := &config{notes: map[string]overrideNote{
+		"example.com/a": {Pos: "o.txt:1", Reason: "until 2024-06", Until: "2024-06", Expires: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		"example.com/b": {Pos: "o.txt:2", Reason: "until 2099-01", Until: "2099-01", Expires: time.Date(2099, 2, 1, 0, 0, 0, 0, time.UTC)},
+		"example.com/c": {Pos: "o.txt:3", Reason: "audited"},
+	}}
+	warnExpired(cfg, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	want := []string{"warning: o.txt:1: the override of example.com/a expired at the end of 2024-06, revisit it: until 2024-06"}
+	if diff := cmp.Diff(want, cfg.warnings); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiles starts the CPU profile requested by -cpuprofile.
+// The returned function, to be called at the end of the scan,
+// stops it and writes the memory profile requested by -memprofile.
+func startProfiles(cfg *config) (stop func() error, err error) {
+	var cpu *os.File
+	stop = func() error {
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				return fmt.Errorf("writing CPU profile: %w", err)
+			}
+		}
+		if cfg.memProfile != "" {
+			return writeMemProfile(cfg.memProfile)
+		}
+		return nil
+	}
+	if cfg.cpuProfile == "" {
+		return stop, nil
+	}
+	f, err := os.Create(cfg.cpuProfile)
+	if err != nil {
+		return nil, fmt.Errorf("creating CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("starting CPU profile: %w", err)
+	}
+	cpu = f
+	return stop, nil
+}
+
+// writeMemProfile writes a heap profile of the scan to file.
+func writeMemProfile(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating memory profile: %w", err)
+	}
+	// Report the allocations of the scan up to its end,
+	// as 'go test -memprofile' does.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing memory profile: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing memory profile: %w", err)
+	}
+	return nil
+}
@@ -16,6 +16,9 @@ import (
 
 // runQuery reports vulnerabilities that apply to the queries in the config.
 func runQuery(ctx context.Context, handler govulncheck.Handler, cfg *config, c *client.Client) error {
+	if cfg.id != "" {
+		return runIDQuery(ctx, handler, cfg.id, c)
+	}
 	reqs := make([]*client.ModuleRequest, len(cfg.patterns))
 	for i, query := range cfg.patterns {
 		mod, ver, err := parseModuleQuery(query)
@@ -51,6 +54,28 @@ func runQuery(ctx context.Context, handler govulncheck.Handler, cfg *config, c *
 	return nil
 }
 
+// runIDQuery reports the vulnerabilities whose ID or alias is id.
+func runIDQuery(ctx context.Context, handler govulncheck.Handler, id string, c *client.Client) error {
+	if err := handler.Progress(&govulncheck.Progress{
+		Message: fmt.Sprintf("Looking up vulnerability %s...", id),
+	}); err != nil {
+		return err
+	}
+	entries, err := c.ByAlias(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no vulnerability with ID or alias %s found in the database", id)
+	}
+	for _, entry := range entries {
+		if err := handler.OSV(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func queryProgressMessage(module, version string) *govulncheck.Progress {
 	return &govulncheck.Progress{
 		Message: fmt.Sprintf("Looking up vulnerabilities in %s at %s...", module, version),
@@ -5,9 +5,11 @@
 package scan
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -16,27 +18,100 @@ import (
 	"time"
 
 	"golang.org/x/telemetry/counter"
+	"golang.org/x/time/rate"
 	"golang.org/x/vuln/internal/client"
 	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/openvex"
 	"golang.org/x/vuln/internal/sarif"
+	"golang.org/x/vuln/internal/sbov"
+	"golang.org/x/vuln/internal/vulncheck"
 )
 
 // RunGovulncheck performs main govulncheck functionality and exits the
 // program upon success with an appropriate exit status. Otherwise,
 // returns an error.
-func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Writer, stderr io.Writer, args []string) error {
+func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Writer, stderr io.Writer, args []string) (err error) {
 	cfg := &config{env: env}
 	if err := parseFlags(cfg, stderr, args); err != nil {
 		return err
 	}
+	warnExpired(cfg, time.Now())
+	stopProfiles, err := startProfiles(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if perr := stopProfiles(); perr != nil {
+			if err != nil {
+				// Keep err, which may carry the exit code.
+				fmt.Fprintln(stderr, perr)
+				return
+			}
+			err = perr
+		}
+	}()
+	if cfg.ScanMode == govulncheck.ScanModeSource {
+		cfg.dir = resolveDir(cfg.dir)
+	}
+	start := time.Now()
 
-	client, err := client.NewClient(cfg.db, nil)
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		scannerVersion(cfg, bi)
+	}
+	client, err := client.NewClient(ctx, cfg.db, &client.Options{
+		UserAgent:  userAgent(cfg),
+		Limiter:    dbLimiter(cfg),
+		StrictOSV:  cfg.strictOSV,
+		KeepRawOSV: cfg.EmitOSV,
+	})
 	if err != nil {
 		return fmt.Errorf("creating client: %w", err)
 	}
 
 	prepareConfig(ctx, cfg, client)
+
+	if cfg.version {
+		// Report the versions of govulncheck and
+		// the database, and exit without scanning.
+		th := NewTextHandler(stdout)
+		th.showVersion = true
+		return th.Config(&cfg.Config)
+	}
+
+	// The results of a module level scan of an unchanged
+	// dependency set can be read from the -cache-dir cache.
+	var cacheFile string
+	var cached []byte
+	if cfg.cacheDir != "" {
+		cacheFile, err = resultCacheFile(cfg, filepath.FromSlash(cfg.dir))
+		if err != nil {
+			return err
+		}
+		cached, err = readResultCache(cacheFile)
+		if err != nil {
+			return err
+		}
+		if cached != nil {
+			// Results are only cached for a single module.
+			if mod := gomodModulePath(filepath.FromSlash(cfg.dir), cfg.env); mod != "" {
+				cfg.MainModules = []string{mod}
+			}
+		}
+	}
+
+	// Packages are loaded before the config is emitted
+	// so that the config can describe what is analyzed.
+	var graph *vulncheck.PackageGraph
+	var loadTime time.Duration
+	if cfg.ScanMode == govulncheck.ScanModeSource && cached == nil {
+		loadStart := time.Now()
+		graph, err = loadSource(cfg, filepath.FromSlash(cfg.dir))
+		if err != nil {
+			return err
+		}
+		loadTime = time.Since(loadStart)
+	}
+
 	var handler govulncheck.Handler
 	switch cfg.format {
 	case formatJSON:
@@ -45,22 +120,92 @@ func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Wr
 		handler = sarif.NewHandler(stdout)
 	case formatOpenVEX:
 		handler = openvex.NewHandler(stdout)
+	case formatHTML:
+		hh := NewHTMLHandler(stdout)
+		hh.advisoryURLTemplate = cfg.advisoryURL
+		handler = hh
+	case formatSBOV:
+		handler = sbov.NewHandler(stdout)
 	default:
+		if cfg.listMods {
+			lh := NewModuleListHandler(stdout)
+			lh.failOn = govulncheck.ScanLevel(cfg.failOn)
+			lh.failOnModules = cfg.failOnMods
+			if cfg.ScanMode == govulncheck.ScanModeSource && !cfg.gopath {
+				// The graph only holds the modules providing
+				// packages, not those of the full build list.
+				if lh.buildList, err = buildList(filepath.FromSlash(cfg.dir), cfg.env); err != nil {
+					return err
+				}
+			}
+			handler = lh
+			break
+		}
+		if cfg.tmpl != nil {
+			th := NewTemplateHandler(stdout, cfg.tmpl)
+			th.failOn = govulncheck.ScanLevel(cfg.failOn)
+			th.failOnModules = cfg.failOnMods
+			handler = th
+			break
+		}
 		th := NewTextHandler(stdout)
 		cfg.show.Update(th)
+		th.failOn = govulncheck.ScanLevel(cfg.failOn)
+		th.failOnModules = cfg.failOnMods
+		th.overrideNotes = cfg.notes
+		th.showAllCVEs = cfg.allCVEs
+		th.listUnreachable = cfg.listUnreach
+		th.showTopPerModule = cfg.topPerMod
+		th.groupByFile = cfg.groupBy == groupFile
+		th.explain = cfg.explain
+		th.maxStackDepth = cfg.maxDepth
+		th.maxResults = cfg.maxResults
+		th.stream = cfg.Stream
+		th.width = cfg.width
+		th.advisoryURLTemplate = cfg.advisoryURL
+		if th.width == 0 {
+			th.width = terminalWidth(stdout)
+		}
+		if th.showPanic && graph != nil {
+			th.moduleDirs = moduleDirs(graph)
+			th.mainPkgs = mainPackages(graph)
+		}
 		handler = th
 	}
 
+	if cacheFile != "" && cached == nil {
+		handler = newCacheHandler(handler, cacheFile)
+	}
+
+	if cfg.exportDB != "" {
+		handler = &snapshotHandler{Handler: handler, dir: cfg.exportDB}
+	}
+
 	if err := handler.Config(&cfg.Config); err != nil {
 		return err
 	}
+	if err := emitWarnings(handler, cfg); err != nil {
+		return err
+	}
 
 	incTelemetryFlagCounters(cfg)
 
+	if cfg.ScanMode == govulncheck.ScanModeSource && cached == nil {
+		if err := vulncheck.EmitTiming(handler, &cfg.Config, "load packages", loadTime); err != nil {
+			return err
+		}
+	}
+
 	switch cfg.ScanMode {
 	case govulncheck.ScanModeSource:
-		dir := filepath.FromSlash(cfg.dir)
-		err = runSource(ctx, handler, cfg, client, dir)
+		if cached != nil {
+			if err = handler.Progress(cacheProgressMessage); err != nil {
+				break
+			}
+			err = govulncheck.HandleJSON(bytes.NewReader(cached), handler)
+			break
+		}
+		err = runSource(ctx, handler, cfg, client, graph)
 	case govulncheck.ScanModeBinary:
 		err = runBinary(ctx, handler, cfg, client)
 	case govulncheck.ScanModeExtract:
@@ -68,17 +213,42 @@ func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Wr
 	case govulncheck.ScanModeQuery:
 		err = runQuery(ctx, handler, cfg, client)
 	case govulncheck.ScanModeConvert:
-		err = govulncheck.HandleJSON(r, handler)
+		err = runConvert(cfg, r, handler)
 	}
 	if err != nil {
 		return err
 	}
+	if err := vulncheck.EmitTiming(handler, &cfg.Config, "total", time.Since(start)); err != nil {
+		return err
+	}
 	return Flush(handler)
 }
 
+// runConvert replays the JSON output of a previous run, read from
+// the -render file if provided and from r otherwise, into handler.
+func runConvert(cfg *config, r io.Reader, handler govulncheck.Handler) error {
+	if cfg.render != "" {
+		f, err := os.Open(cfg.render)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return govulncheck.HandleJSON(r, handler)
+}
+
 func prepareConfig(ctx context.Context, cfg *config, client *client.Client) {
 	cfg.ProtocolVersion = govulncheck.ProtocolVersion
 	cfg.DB = cfg.db
+	if cfg.ScanMode == govulncheck.ScanModeSource {
+		// Record the settings packages are loaded with,
+		// so that the scan can be reproduced.
+		cfg.Patterns = cfg.patterns
+		cfg.Tags = cfg.tags
+		cfg.Test = cfg.test
+		cfg.GOOS, cfg.GOARCH = goPlatform(cfg)
+	}
 	if cfg.ScanMode == govulncheck.ScanModeSource && cfg.GoVersion == "" {
 		const goverPrefix = "GOVERSION="
 		for _, env := range cfg.env {
@@ -86,20 +256,61 @@ func prepareConfig(ctx context.Context, cfg *config, client *client.Client) {
 				cfg.GoVersion = val
 			}
 		}
+		if cfg.GoVersion == "" && !cfg.gopath {
+			// Prefer the version the module says it is built
+			// with over whichever toolchain is on the PATH.
+			cfg.GoVersion = gomodGoVersion(filepath.FromSlash(cfg.dir), cfg.env)
+		}
 		if cfg.GoVersion == "" {
 			if out, err := exec.Command("go", "env", "GOVERSION").Output(); err == nil {
 				cfg.GoVersion = strings.TrimSpace(string(out))
 			}
 		}
 	}
-	if bi, ok := debug.ReadBuildInfo(); ok {
-		scannerVersion(cfg, bi)
-	}
 	if mod, err := client.LastModifiedTime(ctx); err == nil {
 		cfg.DBLastModified = &mod
 	}
 }
 
+// goPlatform returns the GOOS and GOARCH the go command
+// loads packages for in the environment of cfg.
+func goPlatform(cfg *config) (goos, goarch string) {
+	cmd := exec.Command("go", "env", "GOOS", "GOARCH")
+	cmd.Dir = filepath.FromSlash(cfg.dir)
+	cmd.Env = cfg.env
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ""
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return "", ""
+	}
+	return fields[0], fields[1]
+}
+
+// dbLimiter returns the limiter of the requests to the
+// vulnerability database, or nil if -db-rate is not set.
+func dbLimiter(cfg *config) *rate.Limiter {
+	if cfg.dbRate == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(cfg.dbRate), 1)
+}
+
+// userAgent returns the User-Agent used for requests to the
+// vulnerability database, of the form "govulncheck/<version>".
+func userAgent(cfg *config) string {
+	name := cfg.ScannerName
+	if name == "" {
+		name = "govulncheck"
+	}
+	if cfg.ScannerVersion == "" {
+		return name
+	}
+	return name + "/" + cfg.ScannerVersion
+}
+
 // scannerVersion reconstructs the current version of
 // this binary used from the build info.
 func scannerVersion(cfg *config, bi *debug.BuildInfo) {
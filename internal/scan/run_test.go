@@ -5,8 +5,18 @@
 package scan
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime/debug"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
 )
 
 func TestGovulncheckVersion(t *testing.T) {
@@ -24,3 +34,119 @@ func TestGovulncheckVersion(t *testing.T) {
 		t.Errorf("got %s; want %s", got.ScannerVersion, want)
 	}
 }
+
+func TestUserAgent(t *testing.T) {
+	for _, test := range []struct {
+		cfg  *config
+		want string
+	}{
+		{&config{}, "govulncheck"},
+		{&config{Config: govulncheck.Config{ScannerName: "govulncheck", ScannerVersion: "v1.1.0"}}, "govulncheck/v1.1.0"},
+		{&config{Config: govulncheck.Config{ScannerVersion: "v0.0.0-123456789000-20230125195754"}}, "govulncheck/v0.0.0-123456789000-20230125195754"},
+	} {
+		if got := userAgent(test.cfg); got != test.want {
+			t.Errorf("userAgent(%+v) = %q, want %q", test.cfg.Config, got, test.want)
+		}
+	}
+}
+
+func TestGomodGoVersion(t *testing.T) {
+	for _, test := range []struct {
+		gomod string
+		want  string
+	}{
+		{"module example.com/m\n", ""},
+		{"module example.com/m\n\ngo 1.18\n", "go1.18"},
+		{"module example.com/m\n\ngo 1.21.0\n", "go1.21.0"},
+		{"module example.com/m\n\ngo 1.21.0\n\ntoolchain go1.22.3\n", "go1.22.3"},
+		{"module example.com/m\n\ngo 1.21.0\n\ntoolchain default\n", "go1.21.0"},
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(test.gomod), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if got := gomodGoVersion(dir, nil); got != test.want {
+			t.Errorf("gomodGoVersion(%q) = %q, want %q", test.gomod, got, test.want)
+		}
+	}
+}
+
+func TestWorkspacePatterns(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.work":         "go 1.22\n\ntoolchain go1.22.4\n\nuse (\n\t./a\n\t./b\n\t./nested/c\n)\n",
+		"a/go.mod":        "module example.com/a\n\ngo 1.22\n",
+		"b/go.mod":        "module example.com/b\n\ngo 1.22\n",
+		"nested/c/go.mod": "module example.com/c\n\ngo 1.22\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env := append(os.Environ(), "GOWORK=", "GOFLAGS=")
+
+	for _, test := range []struct {
+		dir      string
+		patterns []string
+		want     []string
+	}{
+		{".", []string{"./..."}, []string{"./a/...", "./b/...", "./nested/c/..."}},
+		{".", []string{"example.com/a", "./..."}, []string{"example.com/a", "./a/...", "./b/...", "./nested/c/..."}},
+		{".", nil, []string{"./a/...", "./b/...", "./nested/c/..."}},
+		{".", []string{"./a"}, []string{"./a"}},
+		{"nested", []string{"./..."}, []string{"./c/..."}},
+		// In a module of the workspace, patterns are left alone.
+		{"a", nil, nil},
+	} {
+		got := workspacePatterns(filepath.Join(dir, test.dir), env, test.patterns)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("workspacePatterns(%s, %q) mismatch (-want, +got):\n%s", test.dir, test.patterns, diff)
+		}
+	}
+
+	if got, want := gomodGoVersion(dir, env), "go1.22.4"; got != want {
+		t.Errorf("gomodGoVersion in workspace = %q, want %q", got, want)
+	}
+	if !gomodExists(dir, env) {
+		t.Errorf("gomodExists in workspace = false, want true")
+	}
+}
+
+// TestUnreachableDB checks that a scan fails, and not reports no
+// vulnerabilities, when the database cannot be read, including in
+// json mode, where finding vulnerabilities is not an error.
+func TestUnreachableDB(t *testing.T) {
+	// The database looks like one following the v1 schema,
+	// but fails all requests made during the scan.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.22\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	env := append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+
+	for _, format := range []string{"text", "json"} {
+		var stdout, stderr bytes.Buffer
+		args := []string{"-db", srv.URL, "-C", dir, "-scan", "module", "-format", format}
+		err := RunGovulncheck(context.Background(), env, nil, &stdout, &stderr, args)
+		if err == nil {
+			t.Errorf("-format %s: RunGovulncheck succeeded with an unreachable database", format)
+		}
+		var ec interface{ ExitCode() int }
+		if errors.As(err, &ec) && ec.ExitCode() == 0 {
+			t.Errorf("-format %s: RunGovulncheck exit code = 0, want failure", format)
+		}
+	}
+}
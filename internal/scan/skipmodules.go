@@ -0,0 +1,49 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// readSkipModules reads the file of modules to skip at path. Each
+// non-empty line of the file is a module path. Lines starting with #
+// are comments. The returned paths are sorted and deduplicated.
+func readSkipModules(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSkipModules(path, data)
+}
+
+func parseSkipModules(path string, data []byte) ([]string, error) {
+	seen := make(map[string]bool)
+	var mods []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.ContainsAny(line, " \t") {
+			return nil, fmt.Errorf("%s:%d: want a single module path, got %q", path, n, line)
+		}
+		if !seen[line] {
+			seen[line] = true
+			mods = append(mods, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(mods)
+	return mods, nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+
+	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// snapshotHandler wraps a handler and, on Flush, writes the OSV
+// entries of the stream to a database snapshot, see -export-db.
+type snapshotHandler struct {
+	govulncheck.Handler
+	dir     string
+	entries []*osv.Entry
+}
+
+func (h *snapshotHandler) OSV(e *osv.Entry) error {
+	h.entries = append(h.entries, e)
+	return h.Handler.OSV(e)
+}
+
+// Flush writes the snapshot before flushing the wrapped handler,
+// so that the snapshot is written even if vulnerabilities are found.
+func (h *snapshotHandler) Flush() error {
+	if err := client.WriteDB(h.dir, h.entries); err != nil {
+		return fmt.Errorf("writing database snapshot: %w", err)
+	}
+	return Flush(h.Handler)
+}
@@ -6,7 +6,16 @@ package scan
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal/client"
@@ -15,19 +24,22 @@ import (
 	"golang.org/x/vuln/internal/vulncheck"
 )
 
-// runSource reports vulnerabilities that affect the analyzed packages.
+// loadSource loads the packages matching cfg.patterns in dir and,
+// when analyzing packages, records the number of analyzed packages
+// and modules in cfg.
+// It returns a nil graph if there is nothing to analyze.
 //
-// Vulnerabilities can be called (affecting the package, because a vulnerable
-// symbol is actually exercised) or just imported by the package
-// (likely having a non-affecting outcome).
-func runSource(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client, dir string) (err error) {
+// Errors in the packages are fatal unless -allow-errors is set, in
+// which case they are recorded as warnings in cfg. Call analysis
+// needs well-typed packages, so the scan is then limited to imports.
+func loadSource(cfg *config, dir string) (_ *vulncheck.PackageGraph, err error) {
 	defer derrors.Wrap(&err, "govulncheck")
 
 	if cfg.ScanLevel.WantPackages() && len(cfg.patterns) == 0 {
-		return nil // don't throw an error here
+		return nil, nil // don't throw an error here
 	}
-	if !gomodExists(dir) {
-		return errNoGoMod
+	if !cfg.gopath && !gomodExists(dir, cfg.env) {
+		return nil, errNoGoMod
 	}
 	graph := vulncheck.NewPackageGraph(cfg.GoVersion)
 	pkgConfig := &packages.Config{
@@ -35,15 +47,211 @@ func runSource(ctx context.Context, handler govulncheck.Handler, cfg *config, cl
 		Tests: cfg.test,
 		Env:   cfg.env,
 	}
-	if err := graph.LoadPackagesAndMods(pkgConfig, cfg.tags, cfg.patterns, cfg.ScanLevel == govulncheck.ScanLevelSymbol); err != nil {
+	if cfg.gopath {
+		env := cfg.env
+		if env == nil {
+			env = os.Environ()
+		}
+		pkgConfig.Env = append(slices.Clip(env), "GO111MODULE=off")
+	}
+	patterns := cfg.patterns
+	if !cfg.gopath {
+		patterns = workspacePatterns(dir, cfg.env, patterns)
+	}
+	if cfg.Tools {
+		if patterns, err = toolPackages(pkgConfig, cfg.tags, patterns); err != nil {
+			return nil, err
+		}
+	}
+	err = graph.LoadPackagesAndMods(pkgConfig, cfg.tags, patterns, cfg.ScanLevel == govulncheck.ScanLevelSymbol)
+	if errs := vulncheck.PackageErrors(err); len(errs) > 0 && cfg.allowErrs {
+		for _, e := range errs {
+			cfg.warnf("%v", e)
+		}
+		cfg.warnf("scanning packages with errors, results may be incomplete")
+		if cfg.ScanLevel.WantSymbols() {
+			cfg.warnf("calls cannot be analyzed in packages with errors, scanning at package level")
+			cfg.ScanLevel = govulncheck.ScanLevelPackage
+		}
+		err = nil
+	}
+	if err != nil {
 		if isGoVersionMismatchError(err) {
-			return fmt.Errorf("%v\n\n%v", errGoVersionMismatch, err)
+			return nil, fmt.Errorf("%v\n\n%v", errGoVersionMismatch, err)
+		}
+		if isMissingGoSumError(err) {
+			// Only report the missing entries, other errors
+			// such as failed imports are caused by them.
+			var missing []string
+			for _, e := range vulncheck.PackageErrors(err) {
+				if isMissingGoSumError(e) {
+					missing = append(missing, e.Error())
+				}
+			}
+			if len(missing) == 0 {
+				missing = append(missing, err.Error())
+			}
+			return nil, fmt.Errorf("%v\n\n%s", errNoGoSum, strings.Join(missing, "\n"))
 		}
-		return fmt.Errorf("loading packages: %w", err)
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+	if cfg.gopath {
+		// Packages loaded in GOPATH mode have no module
+		// information, so map them to modules best-effort.
+		graph.ResolveUnknownModules(newGOPATHResolver(cfg.env).resolve)
 	}
-
 	if cfg.ScanLevel.WantPackages() && len(graph.TopPkgs()) == 0 {
-		return nil // early exit
+		// Do not report a clean result when, say, a pattern
+		// contains a typo and nothing is actually analyzed.
+		return nil, fmt.Errorf("no packages matched pattern(s) %s", strings.Join(cfg.patterns, " "))
+	}
+	cfg.MainModules = mainModules(graph)
+	if cfg.ScanLevel.WantPackages() {
+		cfg.PackagesScanned, cfg.ModulesScanned = depPkgsAndMods(graph)
+	}
+	return graph, nil
+}
+
+// toolPackages returns the sorted import paths of the build tools of
+// the packages matching patterns, that is the paths imported by their
+// files constrained by the "tools" build tag, such as a tools.go file
+// with a "//go:build tools" line.
+func toolPackages(cfg *packages.Config, tags, patterns []string) ([]string, error) {
+	c := *cfg
+	c.Mode = packages.NeedName | packages.NeedFiles
+	c.BuildFlags = []string{"-tags=" + strings.Join(append(slices.Clip(tags), "tools"), ",")}
+	// Tools are main packages, which cannot be imported. Loading the
+	// packages importing them hence fails, but their files are known.
+	pkgs, err := packages.Load(&c, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+	seen := make(map[string]bool)
+	var tools []string
+	fset := token.NewFileSet()
+	for _, p := range pkgs {
+		for _, file := range p.GoFiles {
+			f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly|parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			if !isToolsFile(f) {
+				continue
+			}
+			for _, imp := range f.Imports {
+				path, err := strconv.Unquote(imp.Path.Value)
+				if err == nil && !seen[path] {
+					seen[path] = true
+					tools = append(tools, path)
+				}
+			}
+		}
+	}
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no build tools imported by files with the tools build tag in pattern(s) %s", strings.Join(patterns, " "))
+	}
+	slices.Sort(tools)
+	return tools, nil
+}
+
+// isToolsFile reports whether the build constraint of f requires
+// the "tools" build tag.
+func isToolsFile(f *ast.File) bool {
+	for _, g := range f.Comments {
+		if g.Pos() >= f.Package {
+			break
+		}
+		for _, c := range g.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return false
+			}
+			return !expr.Eval(func(tag string) bool { return tag != "tools" })
+		}
+	}
+	return false
+}
+
+// mainModules returns the sorted paths of the main modules in graph.
+func mainModules(graph *vulncheck.PackageGraph) []string {
+	var mods []string
+	for _, m := range graph.Modules() {
+		if m.Main {
+			mods = append(mods, m.Path)
+		}
+	}
+	slices.Sort(mods)
+	return mods
+}
+
+// moduleDirs returns the directories of the modules in graph, keyed
+// by module path. Modules without a known directory, such as vendored
+// ones, are left out.
+func moduleDirs(graph *vulncheck.PackageGraph) map[string]string {
+	dirs := make(map[string]string)
+	for _, m := range graph.Modules() {
+		dir := m.Dir
+		if m.Replace != nil {
+			dir = m.Replace.Dir
+		}
+		if dir != "" {
+			dirs[m.Path] = dir
+		}
+	}
+	return dirs
+}
+
+// mainPackages returns the paths of the main packages in graph.
+func mainPackages(graph *vulncheck.PackageGraph) map[string]bool {
+	mains := make(map[string]bool)
+	for _, p := range graph.TopPkgs() {
+		if p.Name == "main" {
+			mains[p.PkgPath] = true
+		}
+	}
+	return mains
+}
+
+// runSource reports vulnerabilities that affect the analyzed packages.
+//
+// Vulnerabilities can be called (affecting the package, because a vulnerable
+// symbol is actually exercised) or just imported by the package
+// (likely having a non-affecting outcome).
+func runSource(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client, graph *vulncheck.PackageGraph) (err error) {
+	defer derrors.Wrap(&err, "govulncheck")
+
+	if graph == nil {
+		return nil
+	}
+	warnAssumedModules(cfg, graph.Modules())
+	if err := emitWarnings(handler, cfg); err != nil {
+		return err
+	}
+	err = vulncheck.Source(ctx, handler, &cfg.Config, client, graph)
+	var serr *vulncheck.SymbolsError
+	if errors.As(err, &serr) {
+		// The results were reported as those of
+		// a package level scan to the handler.
+		return nil
+	}
+	return err
+}
+
+// depPkgsAndMods returns the number of packages analyzed in graph,
+// that is the top-level packages and their dependencies, and the
+// number of distinct modules these packages belong to.
+func depPkgsAndMods(graph *vulncheck.PackageGraph) (int, int) {
+	tops, deps := graph.TopPkgs(), graph.DepPkgs()
+	mods := make(map[string]bool)
+	for _, pkgs := range [][]*packages.Package{tops, deps} {
+		for _, p := range pkgs {
+			if p.Module != nil {
+				mods[p.Module.Path] = true
+			}
+		}
 	}
-	return vulncheck.Source(ctx, handler, &cfg.Config, client, graph)
+	return len(tops) + len(deps), len(mods)
 }
@@ -5,10 +5,14 @@
 package scan
 
 import (
+	"bytes"
+	"go/parser"
+	"go/token"
 	"strings"
 	"testing"
 
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
 )
 
 func TestSummarizeCallStack(t *testing.T) {
@@ -74,3 +78,63 @@ func stringToFinding(s string) *govulncheck.Finding {
 	}
 	return f
 }
+
+func TestIsToolsFile(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want bool
+	}{
+		{"//go:build tools\n\npackage tools\n", true},
+		{"// Tools of the module.\n\n//go:build tools\n\npackage tools\n", true},
+		{"//go:build tools && !windows\n\npackage tools\n", true},
+		{"//go:build tools || linux\n\npackage tools\n", false},
+		{"//go:build !tools\n\npackage main\n", false},
+		{"//go:build dev\n\npackage main\n", false},
+		{"package main\n\n//go:build tools\n", false},
+		{"package main\n", false},
+	} {
+		f, err := parser.ParseFile(token.NewFileSet(), "tools.go", test.src, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := isToolsFile(f); got != test.want {
+			t.Errorf("isToolsFile(%q) = %t, want %t", test.src, got, test.want)
+		}
+	}
+}
+
+func TestSymbolsFailed(t *testing.T) {
+	for _, failOn := range []govulncheck.ScanLevel{"", govulncheck.ScanLevelSymbol} {
+		var buf bytes.Buffer
+		th := NewTextHandler(&buf)
+		th.failOn = failOn
+		th.listUnreachable = true
+		th.width = 200
+		var h govulncheck.Handler = &snapshotHandler{Handler: th}
+		if err := h.Config(&govulncheck.Config{ScanLevel: govulncheck.ScanLevelSymbol}); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.OSV(&osv.Entry{ID: "GO-0000-0001"}); err != nil {
+			t.Fatal(err)
+		}
+		imported := &govulncheck.Finding{
+			OSV:   "GO-0000-0001",
+			Trace: []*govulncheck.Frame{{Module: "golang.org/vmod", Version: "v0.0.1", Package: "golang.org/vmod/vmod"}},
+		}
+		if err := h.Finding(imported); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Progress(&govulncheck.Progress{Message: "warning: boom", SymbolsFailed: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := th.Flush(); err != errVulnerabilitiesFound {
+			t.Errorf("fail on %q: got %v, want %v", failOn, err, errVulnerabilitiesFound)
+		}
+		if !strings.Contains(buf.String(), "could not be built, so this scan") {
+			t.Errorf("fail on %q: summary does not report the failed symbol analysis:\n%s", failOn, buf.String())
+		}
+		if !strings.Contains(buf.String(), "which vulnerabilities are called is not known") {
+			t.Errorf("fail on %q: unreachable vulnerabilities listed without symbol analysis:\n%s", failOn, buf.String())
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/semver"
 	"golang.org/x/vuln/internal/traces"
 )
 
@@ -31,6 +32,9 @@ type summaryCounters struct {
 	VulnerabilitiesImported int
 	VulnerabilitiesRequired int
 	StdlibCalled            bool
+	// VulnerabilitiesFixed is the number of vulnerabilities
+	// found at the scan level that have a fix available.
+	VulnerabilitiesFixed int
 }
 
 func fixupFindings(osvs []*osv.Entry, findings []*findingSummary) {
@@ -45,12 +49,27 @@ func groupByVuln(findings []*findingSummary) [][]*findingSummary {
 	})
 }
 
+// groupByModule groups findings by module version. A module used at
+// several versions in a build is reported separately for each of them.
 func groupByModule(findings []*findingSummary) [][]*findingSummary {
 	return groupBy(findings, func(left, right *findingSummary) int {
-		return strings.Compare(left.Trace[0].Module, right.Trace[0].Module)
+		return compareModules(left.Trace[0].Module, left.Trace[0].Version, right.Trace[0].Module, right.Trace[0].Version)
 	})
 }
 
+// compareModules orders module versions by path and then by semantic
+// version, so that v1.10.0 comes after v1.9.0.
+func compareModules(path1, version1, path2, version2 string) int {
+	if c := strings.Compare(path1, path2); c != 0 {
+		return c
+	}
+	if c := semver.Compare(version1, version2); c != 0 {
+		return c
+	}
+	// Distinct versions semver cannot order, such as invalid ones.
+	return strings.Compare(version1, version2)
+}
+
 func groupBy(findings []*findingSummary, compare func(left, right *findingSummary) int) [][]*findingSummary {
 	switch len(findings) {
 	case 0:
@@ -139,6 +158,14 @@ func platforms(mod string, e *osv.Entry) []string {
 			continue
 		}
 		for _, p := range a.EcosystemSpecific.Packages {
+			// Explicit os/arch pairs take precedence over
+			// os and arch lists.
+			if len(p.Platforms) > 0 {
+				for _, pl := range p.Platforms {
+					platforms[pl] = true
+				}
+				continue
+			}
 			for _, os := range p.GOOS {
 				// In case there are no specific architectures,
 				// just list the os entries.
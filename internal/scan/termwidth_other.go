@@ -0,0 +1,15 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos)
+
+package scan
+
+import "io"
+
+// terminalWidth returns 0, as terminal sizes
+// are not detected on this platform.
+func terminalWidth(w io.Writer) int {
+	return 0
+}
@@ -7,12 +7,18 @@ package scan
 import (
 	"fmt"
 	"io"
+	"path"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/vuln/internal"
 	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/semver"
+	"golang.org/x/vuln/internal/traces"
 	"golang.org/x/vuln/internal/vulncheck"
 )
 
@@ -38,15 +44,92 @@ type TextHandler struct {
 	sbom      *govulncheck.SBOM
 	osvs      []*osv.Entry
 	findings  []*findingSummary
+	timings   []*govulncheck.Timing
 	scanLevel govulncheck.ScanLevel
 	scanMode  govulncheck.ScanMode
+	failFast  bool
+	// tools is set when the scanned packages
+	// are build tools, see -tools.
+	tools bool
+	// symbolsFailed is set when the call graph could not be built,
+	// which lowers a symbol level scan to the package level.
+	symbolsFailed bool
+
+	packagesScanned int
+	modulesScanned  int
+
+	// overrides maps module paths to the versions
+	// considered fixed locally, see -overrides.
+	overrides map[string]string
+	// overrideNotes are the reasons given
+	// for the overrides, by module path.
+	overrideNotes map[string]overrideNote
+
+	// failOn is the finding level at which vulnerabilities
+	// are reported as found. Defaults to the scan level.
+	failOn govulncheck.ScanLevel
+
+	// failOnModules overrides failOn for
+	// some packages of the scanned code, see -fail-on-modules.
+	failOnModules failLevels
+
+	// maxStackDepth is the number of frames shown from
+	// each end of a call stack, see -max-stack-depth.
+	// Zero means call stacks are shown in full.
+	maxStackDepth int
+
+	// stream prints a line for each called vulnerability
+	// as soon as its first finding arrives, see -stream.
+	stream bool
+	// streamed records the vulnerabilities printed by stream.
+	streamed map[string]bool
+
+	// maxResults is the number of vulnerabilities shown,
+	// see -max-results. Zero means all are shown.
+	maxResults int
+	// shownResults and hiddenResults count the vulnerabilities
+	// shown and left out because of maxResults.
+	shownResults, hiddenResults int
+
+	// width is the width of the lines descriptions and
+	// summaries are wrapped to, see -width. Zero means
+	// defaultLineWidth.
+	width int
+
+	// advisoryURLTemplate is the template of the "More info"
+	// links of vulnerabilities, see -advisory-url-template.
+	advisoryURLTemplate string
 
 	err error
 
 	showColor   bool
 	showTraces  bool
+	showPanic   bool
 	showVersion bool
 	showVerbose bool
+	showAllCVEs bool
+	// listUnreachable lists the vulnerabilities found but not
+	// called, see -list-unreachable.
+	listUnreachable bool
+
+	// showTopPerModule replaces the report with one
+	// line per vulnerable module, see -top-per-module.
+	showTopPerModule bool
+
+	// groupByFile replaces the report with the called
+	// vulnerabilities of each file, see -group-by file.
+	groupByFile bool
+
+	// explain lists the vulnerabilities at every level and
+	// explains the level each was reported at, see -explain.
+	explain bool
+
+	// moduleDirs maps the paths of the analyzed modules to their
+	// directories and mainPkgs holds the paths of the analyzed
+	// main packages, so that -show panic can print the file names
+	// and function names of the runtime.
+	moduleDirs map[string]string
+	mainPkgs   map[string]bool
 }
 
 const (
@@ -58,29 +141,70 @@ const (
 
 	noOtherVulnsMessage = `No other vulnerabilities found.`
 
+	noCallsMessage = `No calls of vulnerable symbols found.`
+
 	verboseMessage = `'-show verbose' for more details`
 
 	symbolMessage = `'-scan symbol' for more fine grained vulnerability detection`
+
+	defaultLineWidth = 80
+
+	// advisoryIDPlaceholder is replaced with the ID of
+	// a vulnerability in the -advisory-url-template.
+	advisoryIDPlaceholder = "{id}"
 )
 
 func (h *TextHandler) Flush() error {
+	if len(h.streamed) > 0 {
+		// Separate the streamed findings from the report.
+		h.print("\n")
+	}
 	if h.showVerbose {
 		h.printSBOM()
 	}
-	if len(h.findings) == 0 {
+	switch {
+	case h.scanMode == govulncheck.ScanModeQuery:
+		// Only lookups by -id can be output as text.
+		h.entries()
+		return h.err
+	case len(h.findings) == 0:
 		h.print(noVulnsMessage + "\n")
-	} else {
+	case h.showTopPerModule:
+		fixupFindings(h.osvs, h.findings)
+		h.topPerModule()
+	case h.groupByFile:
+		fixupFindings(h.osvs, h.findings)
+		h.byFile()
+	default:
 		fixupFindings(h.osvs, h.findings)
 		counters := h.allVulns(h.findings)
 		h.summary(counters)
 	}
+	if len(h.overrides) > 0 {
+		h.clearedByOverride()
+	}
+	if h.listUnreachable && len(h.findings) > 0 {
+		h.unreachable()
+	}
+	if h.showVerbose {
+		h.scanned()
+	}
+	if h.showAllCVEs {
+		h.allCVEs()
+	}
+	if len(h.timings) > 0 {
+		h.printTimings()
+	}
 	if h.err != nil {
 		return h.err
 	}
-	// We found vulnerabilities when the findings' level matches the scan level.
-	if (isCalled(h.findings) && h.scanLevel == govulncheck.ScanLevelSymbol) ||
-		(isImported(h.findings) && h.scanLevel == govulncheck.ScanLevelPackage) ||
-		(isRequired(h.findings) && h.scanLevel == govulncheck.ScanLevelModule) {
+	// We found vulnerabilities when the findings' level matches the
+	// fail level, which is the scan level unless specified otherwise.
+	failOn := h.failOn
+	if failOn == "" {
+		failOn = h.scanLevel
+	}
+	if vulnerabilitiesFound(h.findings, failOn, h.failOnModules) {
 		return errVulnerabilitiesFound
 	}
 
@@ -91,9 +215,19 @@ func (h *TextHandler) Flush() error {
 func (h *TextHandler) Config(config *govulncheck.Config) error {
 	h.scanLevel = config.ScanLevel
 	h.scanMode = config.ScanMode
+	h.failOnModules = h.failOnModules.resolve(config.MainModules)
+	h.packagesScanned = config.PackagesScanned
+	h.modulesScanned = config.ModulesScanned
+	h.overrides = config.Overrides
+	h.failFast = config.FailFast
+	h.tools = config.Tools
 
-	if !h.showVersion {
-		return nil
+	// In convert mode, the settings of the converted
+	// scan follow in the stream.
+	verbose := h.showVerbose && config.ScanMode != govulncheck.ScanModeConvert
+	if !h.showVersion && !verbose {
+		h.scanning(config)
+		return h.err
 	}
 	if config.GoVersion != "" {
 		h.style(keyStyle, "Go: ")
@@ -115,18 +249,85 @@ func (h *TextHandler) Config(config *govulncheck.Config) error {
 			h.print(*config.DBLastModified, "\n")
 		}
 	}
+	if verbose {
+		h.settings(config)
+	}
 	h.print("\n")
+	if !verbose {
+		h.scanning(config)
+	}
 	return h.err
 }
 
+// scanning prints the main modules of config, so that saved
+// reports tell which modules they are for. Verbose output
+// shows them with the other settings instead.
+func (h *TextHandler) scanning(config *govulncheck.Config) {
+	if len(config.MainModules) == 0 {
+		return
+	}
+	h.print("Scanning ", choose(len(config.MainModules) == 1, "module ", "modules "))
+	h.print(strings.Join(config.MainModules, ", "), "...\n\n")
+}
+
+// settings prints the scan settings of config, so that
+// the scan can be reproduced from verbose output.
+func (h *TextHandler) settings(config *govulncheck.Config) {
+	if len(config.MainModules) > 0 {
+		h.style(keyStyle, choose(len(config.MainModules) == 1, "Main module: ", "Main modules: "))
+		h.print(strings.Join(config.MainModules, ", "), "\n")
+	}
+	if config.ScanMode != "" {
+		h.style(keyStyle, "Mode: ")
+		h.print(config.ScanMode, "\n")
+	}
+	if config.ScanLevel != "" {
+		h.style(keyStyle, "Scan level: ")
+		h.print(config.ScanLevel, "\n")
+	}
+	if len(config.Patterns) > 0 {
+		h.style(keyStyle, "Patterns: ")
+		h.print(strings.Join(config.Patterns, " "), "\n")
+	}
+	if len(config.Tags) > 0 {
+		h.style(keyStyle, "Tags: ")
+		h.print(strings.Join(config.Tags, ","), "\n")
+	}
+	if config.Test {
+		h.style(keyStyle, "Tests: ")
+		h.print("included\n")
+	}
+	if config.Depth != "" {
+		h.style(keyStyle, "Depth: ")
+		h.print(config.Depth, "\n")
+	}
+	if config.GOOS != "" {
+		h.style(keyStyle, "Platform: ")
+		h.print(config.GOOS, "/", config.GOARCH, "\n")
+	}
+}
+
 func (h *TextHandler) SBOM(sbom *govulncheck.SBOM) error {
 	h.sbom = sbom
 	return nil
 }
 
+// Graph does nothing, import graphs are only supported in JSON output.
+func (h *TextHandler) Graph(graph *govulncheck.Graph) error {
+	return nil
+}
+
+// Timing records the duration of a phase of the scan,
+// printed after the results.
+func (h *TextHandler) Timing(timing *govulncheck.Timing) error {
+	h.timings = append(h.timings, timing)
+	return nil
+}
+
 func (h *TextHandler) printSBOM() error {
+	// Patterns matching no packages fail the scan, so a missing
+	// SBOM only means that the results were converted or cached.
 	if h.sbom == nil {
-		h.print("No packages matched the provided pattern.\n")
 		return nil
 	}
 
@@ -166,14 +367,27 @@ func (h *TextHandler) printSBOM() error {
 	return nil
 }
 
-// Progress writes progress updates during govulncheck execution.
+// Progress writes progress updates during govulncheck execution,
+// if -show verbose is set. Warnings are always written.
 func (h *TextHandler) Progress(progress *govulncheck.Progress) error {
-	if h.showVerbose {
+	if progress.SymbolsFailed {
+		h.symbolsFailed = true
+		h.scanLevel = withoutSymbols(h.scanLevel)
+		h.failOn = withoutSymbols(h.failOn)
+		h.failOnModules = h.failOnModules.withoutSymbols()
+	}
+	if h.showVerbose || isWarning(progress) {
 		h.print(progress.Message, "\n\n")
 	}
 	return h.err
 }
 
+// isWarning reports whether progress is a warning, that is
+// whether its message starts with "warning: ".
+func isWarning(progress *govulncheck.Progress) bool {
+	return strings.HasPrefix(progress.Message, "warning: ")
+}
+
 // OSV gathers osv entries to be written.
 func (h *TextHandler) OSV(entry *osv.Entry) error {
 	h.osvs = append(h.osvs, entry)
@@ -185,8 +399,16 @@ func (h *TextHandler) Finding(finding *govulncheck.Finding) error {
 	if err := validateFindings(finding); err != nil {
 		return err
 	}
-	h.findings = append(h.findings, newFindingSummary(finding))
-	return nil
+	fs := newFindingSummary(finding)
+	h.findings = append(h.findings, fs)
+	if h.stream && fs.Compact != "" && !h.streamed[finding.OSV] {
+		if h.streamed == nil {
+			h.streamed = make(map[string]bool)
+		}
+		h.streamed[finding.OSV] = true
+		h.print("Found ", finding.OSV, ": ", fs.Compact, "\n")
+	}
+	return h.err
 }
 
 func (h *TextHandler) allVulns(findings []*findingSummary) summaryCounters {
@@ -211,32 +433,33 @@ func (h *TextHandler) allVulns(findings []*findingSummary) summaryCounters {
 	}
 
 	if h.scanLevel.WantSymbols() {
-		h.style(sectionStyle, "=== Symbol Results ===\n\n")
-		if len(called) == 0 {
-			h.print(noVulnsMessage, "\n\n")
-		}
-		for index, findings := range called {
-			h.vulnerability(index, findings)
-		}
+		h.results("Symbol", noVulnsMessage, called)
 	}
 
-	if h.scanLevel == govulncheck.ScanLevelPackage || (h.scanLevel.WantPackages() && h.showVerbose) {
-		h.style(sectionStyle, "=== Package Results ===\n\n")
-		if len(imported) == 0 {
-			h.print(choose(!h.scanLevel.WantSymbols(), noVulnsMessage, noOtherVulnsMessage), "\n\n")
-		}
-		for index, findings := range imported {
-			h.vulnerability(index, findings)
-		}
+	if h.scanLevel == govulncheck.ScanLevelPackage || (h.scanLevel.WantPackages() && (h.showVerbose || h.explain)) {
+		h.results("Package", choose(!h.scanLevel.WantSymbols(), noVulnsMessage, noOtherVulnsMessage), imported)
 	}
 
-	if h.showVerbose || h.scanLevel == govulncheck.ScanLevelModule {
-		h.style(sectionStyle, "=== Module Results ===\n\n")
-		if len(required) == 0 {
-			h.print(choose(!h.scanLevel.WantPackages(), noVulnsMessage, noOtherVulnsMessage), "\n\n")
-		}
-		for index, findings := range required {
-			h.vulnerability(index, findings)
+	if h.showVerbose || h.explain || h.scanLevel == govulncheck.ScanLevelModule {
+		h.results("Module", choose(!h.scanLevel.WantPackages(), noVulnsMessage, noOtherVulnsMessage), required)
+	}
+
+	if h.hiddenResults > 0 {
+		h.print("... and ", h.hiddenResults, " more (use -json for all)\n\n")
+	}
+
+	// count the vulnerabilities at the scan level that have a fix
+	atLevel := required
+	switch h.scanLevel {
+	case govulncheck.ScanLevelSymbol:
+		atLevel = called
+	case govulncheck.ScanLevelPackage:
+		atLevel = imported
+	}
+	var fixed int
+	for _, findings := range atLevel {
+		if hasFix(findings) {
+			fixed++
 		}
 	}
 
@@ -246,6 +469,41 @@ func (h *TextHandler) allVulns(findings []*findingSummary) summaryCounters {
 		VulnerabilitiesRequired: len(required),
 		ModulesCalled:           len(mods),
 		StdlibCalled:            stdlibCalled,
+		VulnerabilitiesFixed:    fixed,
+	}
+}
+
+// hasFix reports whether findings, all for the same vulnerability,
+// can be resolved by upgrading, that is whether each affected module
+// has a fixed version.
+func hasFix(findings []*findingSummary) bool {
+	for _, f := range findings {
+		if f.FixedVersion == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// results prints a section of the results, with the vulnerabilities
+// found at one level, or none if there are none. Once maxResults
+// vulnerabilities are printed, the others are only counted.
+func (h *TextHandler) results(level, none string, vulns [][]*findingSummary) {
+	if h.maxResults > 0 && h.shownResults >= h.maxResults && len(vulns) > 0 {
+		h.hiddenResults += len(vulns)
+		return
+	}
+	h.style(sectionStyle, "=== ", level, choose(h.tools, " Results for Build Tools ===\n\n", " Results ===\n\n"))
+	if len(vulns) == 0 {
+		h.print(none, "\n\n")
+	}
+	for index, findings := range vulns {
+		if h.maxResults > 0 && h.shownResults >= h.maxResults {
+			h.hiddenResults++
+			continue
+		}
+		h.shownResults++
+		h.vulnerability(index, findings)
 	}
 }
 
@@ -263,11 +521,25 @@ func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
 	if description == "" {
 		description = findings[0].OSV.Details
 	}
-	h.wrap("    ", description, 80)
+	h.wrap("    ", description, h.lineWidth())
 	h.style(defaultStyle)
 	h.print("\n")
 	h.style(keyStyle, "  More info:")
-	h.print(" ", findings[0].OSV.DatabaseSpecific.URL, "\n")
+	h.print(" ", advisoryURL(h.advisoryURLTemplate, findings[0].OSV), "\n")
+	if h.showVerbose && len(findings[0].OSV.Aliases) > 0 {
+		h.style(keyStyle, "  Aliases:")
+		h.print(" ", strings.Join(findings[0].OSV.Aliases, ", "), "\n")
+	}
+	if cwes := weaknesses(findings[0].OSV); h.showVerbose && len(cwes) > 0 {
+		h.style(keyStyle, choose(len(cwes) == 1, "  Weakness:", "  Weaknesses:"))
+		h.print(" ", strings.Join(cwes, ", "), "\n")
+	}
+	if h.showVerbose {
+		for _, url := range fixLinks(findings[0].OSV) {
+			h.style(keyStyle, "  Fix:")
+			h.print(" ", url, "\n")
+		}
+	}
 
 	byModule := groupByModule(findings)
 	first := true
@@ -301,7 +573,11 @@ func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
 		}
 		h.print("\n    ")
 		h.style(keyStyle, "Found in: ")
-		h.print(path, "@", foundVersion, "\n    ")
+		h.print(path, "@", foundVersion)
+		if matched := module[0].MatchedVersion; matched != "" {
+			h.print(" (matched as ", moduleVersionString(mod, matched), ")")
+		}
+		h.print("\n    ")
 		h.style(keyStyle, "Fixed in: ")
 		if fixedVersion != "" {
 			h.print(path, "@", fixedVersion)
@@ -309,6 +585,17 @@ func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
 			h.print("N/A")
 		}
 		h.print("\n")
+		if introduced := module[0].IntroducedVersion; h.showVerbose && introduced != "" {
+			h.style(keyStyle, "    Introduced in: ")
+			if introduced == "v0.0.0" {
+				// The OSV report states the vulnerability is
+				// present from the very first version.
+				h.print("first version")
+			} else {
+				h.print(path, "@", moduleVersionString(lastFrame.Module, introduced))
+			}
+			h.print("\n")
+		}
 		platforms := platforms(mod, module[0].OSV)
 		if len(platforms) > 0 {
 			h.style(keyStyle, "    Platforms: ")
@@ -320,11 +607,110 @@ func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
 			}
 			h.print("\n")
 		}
+		if h.scanLevel == govulncheck.ScanLevelModule {
+			// Module scans do not look at the code, so an absence
+			// of traces says nothing about the vulnerability being
+			// called.
+			h.style(keyStyle, "    Reachability: ")
+			h.print("not analyzed (module scan)\n")
+		}
+		if h.explain {
+			h.style(keyStyle, "    Explanation:")
+			h.print("\n")
+			h.wrap("      ", h.explanation(module), h.lineWidth())
+			h.print("\n")
+		}
 		h.traces(module)
 	}
 	h.print("\n")
 }
 
+// explanation describes how the findings of a vulnerability in a
+// module were narrowed down from the vulnerable module version to
+// the vulnerable packages imported and the vulnerable symbols called,
+// and the level at which the vulnerability is reported as a result.
+func (h *TextHandler) explanation(findings []*findingSummary) string {
+	frame := findings[0].Trace[0]
+	var steps []string
+	if frame.Module == internal.GoStdModulePath {
+		steps = append(steps, fmt.Sprintf("standard library at vulnerable version %s", moduleVersionString(frame.Module, frame.Version)))
+	} else {
+		mod := frame.Module
+		if frame.Version != "" {
+			mod += "@" + frame.Version
+		}
+		steps = append(steps, "module "+mod+" at vulnerable version")
+	}
+	if h.scanLevel == govulncheck.ScanLevelModule {
+		steps = append(steps, "packages not analyzed (module scan)", "reported at module level")
+		return strings.Join(steps, " → ")
+	}
+
+	var pkgs, called []string
+	for _, f := range findings {
+		if p := f.Trace[0].Package; p != "" && !slices.Contains(pkgs, p) {
+			pkgs = append(pkgs, p)
+		}
+		if f.Trace[0].Function != "" {
+			if s := symbol(f.Trace[0], true); !slices.Contains(called, s) {
+				called = append(called, s)
+			}
+		}
+	}
+	sort.Strings(pkgs)
+	sort.Strings(called)
+	imported := plural("package", pkgs) + choose(h.scanMode == govulncheck.ScanModeBinary, " in binary", " imported")
+	switch {
+	case len(pkgs) == 0:
+		steps = append(steps, "no vulnerable package imported", "reported at module level")
+	case h.scanLevel == govulncheck.ScanLevelPackage:
+		steps = append(steps, imported, "symbols not analyzed (package scan)", "reported at package level")
+	case len(called) > 0:
+		found := choose(h.scanMode == govulncheck.ScanModeBinary, "present in binary", "reached in call graph")
+		steps = append(steps, imported, plural("symbol", called)+" "+found, "reported at symbol level")
+	default:
+		notFound := choose(h.scanMode == govulncheck.ScanModeBinary, "not present in binary", "not reached in call graph")
+		steps = append(steps, imported)
+		if syms := vulnerableSymbols(findings[0].OSV, frame.Module, pkgs); len(syms) > 0 {
+			steps = append(steps, plural("symbol", syms)+" "+notFound)
+		} else {
+			steps = append(steps, "vulnerable symbols "+notFound)
+		}
+		steps = append(steps, "reported at package level")
+	}
+	return strings.Join(steps, " → ")
+}
+
+// vulnerableSymbols returns the symbols of the packages pkgs of
+// module mod that are vulnerable according to e, qualified by the
+// package name, or nil if e does not list them.
+func vulnerableSymbols(e *osv.Entry, mod string, pkgs []string) []string {
+	var syms []string
+	for _, a := range e.Affected {
+		if a.Module.Path != mod {
+			continue
+		}
+		for _, p := range a.EcosystemSpecific.Packages {
+			if !slices.Contains(pkgs, p.Path) {
+				continue
+			}
+			for _, s := range p.Symbols {
+				if s := path.Base(p.Path) + "." + s; !slices.Contains(syms, s) {
+					syms = append(syms, s)
+				}
+			}
+		}
+	}
+	sort.Strings(syms)
+	return syms
+}
+
+// plural returns noun followed by the comma-separated names,
+// with noun in plural form if there are several names.
+func plural(noun string, names []string) string {
+	return choose(len(names) == 1, noun, noun+"s") + " " + strings.Join(names, ", ")
+}
+
 // pkg gives the package information for findings summaries
 // if one exists. This is only used to print package path
 // instead of a module for stdlib vulnerabilities at symbol
@@ -350,12 +736,17 @@ func (h *TextHandler) traces(traces []*findingSummary) {
 
 	// compacts are finding summaries with compact traces
 	// suitable for non-verbose textual output. Currently,
-	// only traces produced by symbol analysis.
+	// only traces produced by symbol analysis. Different
+	// traces to a symbol can have the same compact form,
+	// which is then shown only once.
 	var compacts []*findingSummary
+	seen := make(map[string]bool)
 	for _, t := range traces {
-		if t.Compact != "" {
-			compacts = append(compacts, t)
+		if t.Compact == "" || (!h.showTraces && seen[t.Compact]) {
+			continue
 		}
+		seen[t.Compact] = true
+		compacts = append(compacts, t)
 	}
 
 	// binLimit is a limit on the number of binary traces
@@ -382,7 +773,12 @@ func (h *TextHandler) traces(traces []*findingSummary) {
 		h.print("      #", i+1, ": ")
 
 		if !h.showTraces { // show summarized traces
-			h.print(entry.Compact, "\n")
+			h.print(entry.Compact)
+			if throughGenerated(entry.Trace) {
+				h.print(" (through generated code)")
+			}
+			h.print("\n")
+			h.entryPoints(entry.Finding)
 			continue
 		}
 
@@ -390,19 +786,128 @@ func (h *TextHandler) traces(traces []*findingSummary) {
 			// There are no call stacks in binary mode
 			// so just show the full symbol name.
 			h.print(symbol(entry.Trace[0], false), "\n")
+		} else if h.showPanic {
+			h.print("for function ", symbol(entry.Trace[0], false), "\n")
+			h.panicTrace(entry.Trace)
+			h.entryPoints(entry.Finding)
 		} else {
 			h.print("for function ", symbol(entry.Trace[0], false), "\n")
 			for i := len(entry.Trace) - 1; i >= 0; i-- {
+				if n := h.elided(len(entry.Trace), i); n > 0 {
+					h.print("        ... ", n, choose(n == 1, " frame", " frames"), " elided ...\n")
+					i -= n - 1
+					continue
+				}
 				t := entry.Trace[i]
 				h.print("        ")
 				h.print(symbolName(t))
 				if t.Position != nil {
 					h.print(" @ ", symbolPath(t))
 				}
+				if t.Generated {
+					h.print(" (generated)")
+				}
 				h.print("\n")
 			}
+			h.entryPoints(entry.Finding)
+		}
+	}
+}
+
+// throughGenerated reports whether a call stack passes
+// through functions declared in generated files.
+func throughGenerated(trace []*govulncheck.Frame) bool {
+	for _, f := range trace {
+		if f.Generated {
+			return true
+		}
+	}
+	return false
+}
+
+// panicTrace prints trace in the format of the stack traces of
+// a Go panic, the vulnerable symbol first, so that tools parsing
+// those traces can be used on it. Source analysis has no program
+// counters, so all frames have a +0x0 offset.
+func (h *TextHandler) panicTrace(trace []*govulncheck.Frame) {
+	h.print("goroutine 1 [running]:\n")
+	for i := 0; i < len(trace); i++ {
+		if n := h.elided(len(trace), len(trace)-1-i); n > 0 {
+			h.print("...additional frames elided...\n")
+			i += n - 1
+			continue
+		}
+		t := trace[i]
+		h.print(h.panicName(t), "(...)\n\t")
+		if p := t.Position; p != nil && p.Line > 0 {
+			h.print(h.panicFile(t), ":", p.Line, " +0x0\n")
+		} else {
+			h.print("?:0 +0x0\n")
+		}
+	}
+}
+
+// panicName returns the name of the function of frame t
+// as shown by the runtime, such as pkg/path.(*T).Method
+// or pkg/path.F.func1. Functions of main packages are
+// qualified by main, as in main.F.
+func (h *TextHandler) panicName(t *govulncheck.Frame) string {
+	name := t.Function
+	if fn, closure, ok := strings.Cut(name, "$"); ok {
+		name = fn + ".func" + strings.ReplaceAll(closure, "$", ".")
+	}
+	switch {
+	case strings.HasPrefix(t.Receiver, "*"):
+		name = "(" + t.Receiver + ")." + name
+	case t.Receiver != "":
+		name = t.Receiver + "." + name
+	}
+	if h.mainPkgs[t.Package] {
+		return "main." + name
+	}
+	return t.Package + "." + name
+}
+
+// panicFile returns the absolute, slash-separated name of the
+// file of frame t. It falls back to a name relative to the module
+// path when the directory of the module is not known.
+func (h *TextHandler) panicFile(t *govulncheck.Frame) string {
+	if dir, ok := h.moduleDirs[t.Module]; ok {
+		return filepath.ToSlash(filepath.Join(dir, filepath.FromSlash(t.Position.Filename)))
+	}
+	return t.Module + "/" + t.Position.Filename
+}
+
+// entryPoints prints the entry functions the vulnerable symbol of f
+// is reachable from, see -print-reachable-functions. Without that
+// flag, their number is printed with '-show verbose'.
+func (h *TextHandler) entryPoints(f *govulncheck.Finding) {
+	frames := f.EntryPoints
+	if len(frames) == 0 {
+		if n := f.EntryPointCount; n > 0 && h.showVerbose {
+			h.print("        Reachable from ", n, choose(n == 1, " entry function\n", " entry functions\n"))
 		}
+		return
 	}
+	h.print("        Reachable from ", len(frames), choose(len(frames) == 1, " entry function:\n", " entry functions:\n"))
+	for _, f := range frames {
+		h.print("          ", symbol(f, true))
+		if f.Position != nil {
+			h.print(" @ ", symbolPath(f))
+		}
+		h.print("\n")
+	}
+}
+
+// elided returns the number of frames to skip from index i of a trace
+// of length n, printed from its last frame to its first, so that only
+// maxStackDepth frames from each end of the trace are shown.
+func (h *TextHandler) elided(n, i int) int {
+	d := h.maxStackDepth
+	if d == 0 || n <= 2*d || i != n-1-d {
+		return 0
+	}
+	return n - 2*d
 }
 
 // symbolPath returns a user-friendly path to a symbol.
@@ -415,7 +920,11 @@ func symbolPath(t *govulncheck.Frame) string {
 func (h *TextHandler) summary(c summaryCounters) {
 	// print short summary of findings identified at the desired level of scan precision
 	var vulnCount int
-	h.print("Your code ", choose(h.scanLevel.WantSymbols(), "is", "may be"), " affected by ")
+	if h.tools {
+		h.print("Your build tools ", choose(h.scanLevel.WantSymbols(), "are", "may be"), " affected by ")
+	} else {
+		h.print("Your code ", choose(h.scanLevel.WantSymbols(), "is", "may be"), " affected by ")
+	}
 	switch h.scanLevel {
 	case govulncheck.ScanLevelSymbol:
 		vulnCount = c.VulnerabilitiesCalled
@@ -441,16 +950,357 @@ func (h *TextHandler) summary(c summaryCounters) {
 	}
 	h.print(".\n")
 
-	// print summary for vulnerabilities found at other levels of scan precision
-	if other := h.summaryOtherVulns(c); other != "" {
-		h.wrap("", other, 80)
+	// print how many of the vulnerabilities above can be fixed by upgrading
+	if vulnCount > 0 {
+		noFix := vulnCount - c.VulnerabilitiesFixed
+		h.wrap("", fmt.Sprintf("Of these, %d %s a fix available and %d %s not.",
+			c.VulnerabilitiesFixed, choose(c.VulnerabilitiesFixed == 1, "has", "have"),
+			noFix, choose(noFix == 1, "does", "do")), h.lineWidth())
+		h.print("\n")
+	}
+
+	// With -fail-fast, the vulnerabilities found at other levels
+	// of scan precision might be called as well.
+	if h.failFast && vulnCount > 0 {
+		h.wrap("", "The scan stopped at the first called vulnerability (-fail-fast), so your code might be affected by more.", h.lineWidth())
+		h.print("\n")
+	} else if other := h.summaryOtherVulns(c); other != "" {
+		h.wrap("", other, h.lineWidth())
 		h.print("\n")
 	}
 
 	// print suggested flags for more/better info depending on scan level and if in verbose mode
 	if sugg := h.summarySuggestion(); sugg != "" {
-		h.wrap("", sugg, 80)
+		h.wrap("", sugg, h.lineWidth())
+		h.print("\n")
+	}
+}
+
+// topPerModule prints one line per module with vulnerabilities, naming
+// the vulnerability found at the most precise level, called before
+// imported before required, and the number of other vulnerabilities.
+func (h *TextHandler) topPerModule() {
+	for _, findings := range groupByModule(h.findings) {
+		var top []*findingSummary
+		vulns := groupByVuln(findings)
+		// groupByVuln sorts in reverse order of IDs, so iterate
+		// backwards for the lowest ID to win ties.
+		for i := len(vulns) - 1; i >= 0; i-- {
+			if top == nil || reachability(vulns[i]) > reachability(top) {
+				top = vulns[i]
+			}
+		}
+		mod := top[0].Trace[0]
+		h.print(mod.Module)
+		if mod.Version != "" {
+			h.print("@", mod.Version)
+		}
+		h.print(": ")
+		h.style(choose(isCalled(top), osvCalledStyle, osvImportedStyle), top[0].OSV.ID)
+		h.print(" (", findingLevel(top), ")")
+		if n := len(vulns) - 1; n > 0 {
+			h.print(" and ", n, choose(n == 1, " other vulnerability", " other vulnerabilities"))
+		}
+		h.print("\n")
+	}
+}
+
+// byFile prints each source file with calls of vulnerable symbols,
+// sorted by name, followed by the calls in it. The file of a call
+// is the file of the frame where the compact trace of the finding
+// leaves the code being scanned.
+func (h *TextHandler) byFile() {
+	calls := make(map[string][]*findingSummary)
+	for _, f := range h.findings {
+		compact := traces.Compact(f.Finding)
+		if f.Trace[0].Function == "" || len(compact) == 0 {
+			continue
+		}
+		p := compact[len(compact)-1].Position
+		if p == nil || p.Filename == "" {
+			continue
+		}
+		file := AbsRelShorter(p.Filename)
+		calls[file] = append(calls[file], f)
+	}
+	if len(calls) == 0 {
+		h.print(noCallsMessage, "\n")
+		return
+	}
+	files := make([]string, 0, len(calls))
+	for file := range calls {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for i, file := range files {
+		if i > 0 {
+			h.print("\n")
+		}
+		h.style(keyStyle, file, ":")
+		h.print("\n")
+		fs := calls[file]
+		sort.SliceStable(fs, func(i, j int) bool {
+			if fs[i].OSV.ID != fs[j].OSV.ID {
+				return fs[i].OSV.ID < fs[j].OSV.ID
+			}
+			return fs[i].Compact < fs[j].Compact
+		})
+		var last string
+		for _, f := range fs {
+			line := f.OSV.ID + ": " + f.Compact
+			if line == last {
+				continue // several traces for the same call
+			}
+			last = line
+			h.print("  ")
+			h.style(osvCalledStyle, f.OSV.ID)
+			h.print(": ", f.Compact, "\n")
+		}
+	}
+}
+
+// entries prints the OSV entries found by a lookup, with
+// the modules they affect and the versions fixing them.
+func (h *TextHandler) entries() {
+	for _, e := range h.osvs {
+		h.style(keyStyle, "Vulnerability: ")
+		h.style(osvCalledStyle, e.ID)
+		h.print("\n")
+		h.style(detailsStyle)
+		h.wrap("    ", e.Details, h.lineWidth())
+		h.style(defaultStyle)
+		h.print("\n")
+		h.style(keyStyle, "  More info:")
+		h.print(" ", advisoryURL(h.advisoryURLTemplate, e), "\n")
+		if len(e.Aliases) > 0 {
+			h.style(keyStyle, "  Aliases:")
+			h.print(" ", strings.Join(e.Aliases, ", "), "\n")
+		}
+		seen := make(map[string]bool)
+		for _, a := range e.Affected {
+			mod := a.Module.Path
+			if seen[mod] {
+				continue
+			}
+			seen[mod] = true
+			h.print("  ")
+			if mod == internal.GoStdModulePath {
+				h.print("Standard library")
+			} else {
+				h.style(keyStyle, "Module: ")
+				h.print(mod)
+			}
+			h.print("\n    ")
+			h.style(keyStyle, "Fixed in: ")
+			fixes := vulncheck.FixedVersions(mod, "", e.Affected)
+			if len(fixes) == 0 {
+				h.print("N/A")
+			}
+			for i, fix := range fixes {
+				if i > 0 {
+					h.print(", ")
+				}
+				h.print(mod, "@", moduleVersionString(mod, fix))
+			}
+			h.print("\n")
+		}
+		h.print("\n")
+	}
+}
+
+// reachability ranks findings for the same vulnerability
+// by the most precise level at which they were found.
+func reachability(findings []*findingSummary) int {
+	switch {
+	case isCalled(findings):
+		return 2
+	case isImported(findings):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// scanned prints the number of analyzed packages and modules, if known.
+func (h *TextHandler) scanned() {
+	if h.packagesScanned == 0 {
+		return
+	}
+	h.print("Analyzed ")
+	h.style(valueStyle, h.packagesScanned)
+	h.print(choose(h.packagesScanned == 1, " package", " packages"), " across ")
+	h.style(valueStyle, h.modulesScanned)
+	h.print(choose(h.modulesScanned == 1, " module", " modules"), ".\n")
+}
+
+// printTimings prints the duration of each phase of the scan,
+// in the order the phases completed.
+func (h *TextHandler) printTimings() {
+	width := 0
+	for _, t := range h.timings {
+		width = max(width, len(t.Phase))
+	}
+	h.print("\n")
+	h.style(sectionStyle, "=== Timings ===\n\n")
+	for _, t := range h.timings {
+		h.print(fmt.Sprintf("%-*s  ", width, t.Phase))
+		h.style(valueStyle, t.Duration.Round(time.Millisecond))
+		h.print("\n")
+	}
+}
+
+// clearedByOverride prints the vulnerabilities that would affect
+// the required modules if these modules were not considered fixed
+// locally by an override.
+//
+// As in the scan, the vulnerabilities of a module replaced by another
+// one are those of the replacement, which the SBOM lists, and its
+// override is looked up by the path of the module or else of its
+// replacement.
+func (h *TextHandler) clearedByOverride() {
+	if h.sbom == nil {
+		return
+	}
+	modules := make(map[string]*govulncheck.Module)
+	for _, m := range h.sbom.Modules {
+		modules[m.Path] = m
+	}
+	var cleared []string
+	for _, entry := range h.osvs {
+		for _, a := range entry.Affected {
+			m := modules[a.Module.Path]
+			if m == nil || m.Version == "" {
+				continue
+			}
+			path, fixed, ok := overrideOf(m, h.overrides)
+			if !ok || semver.Less(m.Version, fixed) {
+				continue
+			}
+			if semver.Affects(a.Ranges, m.Version) {
+				c := fmt.Sprintf("%s: %s@%s (fixed locally at %s)", entry.ID, a.Module.Path, m.Version, fixed)
+				if n, ok := h.overrideNotes[path]; ok {
+					c += ": " + n.Reason
+					if n.expired(time.Now()) {
+						c += " [expired]"
+					}
+				}
+				cleared = append(cleared, c)
+				break
+			}
+		}
+	}
+	if len(cleared) == 0 {
+		return
+	}
+	sort.Strings(cleared)
+	h.print("\n")
+	h.style(sectionStyle, "=== Cleared by Override ===\n\n")
+	for _, c := range cleared {
+		h.print(c, "\n")
+	}
+}
+
+// overrideOf returns the module path under which overrides has a
+// fixed version for m, and that version. The path of the module m
+// replaces, if any, takes precedence over the path of m.
+func overrideOf(m *govulncheck.Module, overrides map[string]string) (path, fixed string, ok bool) {
+	if m.Replaces != "" {
+		if fixed, ok := overrides[m.Replaces]; ok {
+			return m.Replaces, fixed, true
+		}
+	}
+	fixed, ok = overrides[m.Path]
+	return m.Path, fixed, ok
+}
+
+// allCVEs prints the deduplicated and sorted list of CVE and
+// GHSA aliases of all vulnerabilities found, one per line.
+func (h *TextHandler) allCVEs() {
+	seen := make(map[string]bool)
+	var aliases []string
+	for _, f := range h.findings {
+		if f.OSV == nil {
+			continue
+		}
+		for _, a := range f.OSV.Aliases {
+			if !strings.HasPrefix(a, "CVE-") && !strings.HasPrefix(a, "GHSA-") {
+				continue
+			}
+			if !seen[a] {
+				seen[a] = true
+				aliases = append(aliases, a)
+			}
+		}
+	}
+	if len(aliases) == 0 {
+		return
+	}
+	sort.Strings(aliases)
+	h.print("\n")
+	h.style(sectionStyle, "=== CVE and GHSA Aliases ===\n\n")
+	for _, a := range aliases {
+		h.print(a, "\n")
+	}
+}
+
+// unreachable prints the vulnerabilities that were found in imported
+// packages or required modules but are not called, with the module
+// versions they were found in, see -list-unreachable.
+func (h *TextHandler) unreachable() {
+	var imported, required [][]*findingSummary
+	for _, findings := range groupByVuln(h.findings) {
+		switch {
+		case isCalled(findings):
+		case isImported(findings):
+			imported = append(imported, findings)
+		default:
+			required = append(required, findings)
+		}
+	}
+	h.print("\n")
+	h.style(sectionStyle, "=== Unreachable Vulnerabilities ===\n\n")
+	if h.symbolsFailed {
+		h.wrap("", "The call graph of your code could not be built, so which vulnerabilities are called is not known.", h.lineWidth())
 		h.print("\n")
+		return
+	}
+	if len(imported)+len(required) == 0 {
+		h.print("All the vulnerabilities found are called.\n")
+		return
+	}
+	h.unreachableGroup("Imported but not called:", imported)
+	if len(imported) > 0 && len(required) > 0 {
+		h.print("\n")
+	}
+	h.unreachableGroup("Required but not imported:", required)
+}
+
+// unreachableGroup prints the vulnerabilities of one kind
+// listed by unreachable under title.
+func (h *TextHandler) unreachableGroup(title string, vulns [][]*findingSummary) {
+	if len(vulns) == 0 {
+		return
+	}
+	h.style(keyStyle, title)
+	h.print("\n")
+	for _, findings := range vulns {
+		for _, module := range groupByModule(findings) {
+			frame := module[0].Trace[0]
+			h.print("  ")
+			h.style(osvImportedStyle, findings[0].OSV.ID)
+			h.print(" in ", frame.Module, "@", moduleVersionString(frame.Module, frame.Version))
+			var pkgs []string
+			for _, f := range module {
+				if p := f.Trace[0].Package; p != "" && !slices.Contains(pkgs, p) {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) > 0 {
+				sort.Strings(pkgs)
+				h.print(" (", strings.Join(pkgs, ", "), ")")
+			}
+			h.print("\n")
+		}
 	}
 }
 
@@ -473,7 +1323,14 @@ func (h *TextHandler) summaryOtherVulns(c summaryCounters) string {
 			summary.WriteString(fmt.Sprint(c.VulnerabilitiesRequired))
 			summary.WriteString(choose(c.VulnerabilitiesRequired == 1, ` vulnerability `, ` vulnerabilities `))
 			summary.WriteString("in modules you require")
-			summary.WriteString(choose(h.scanLevel.WantSymbols(), ", but your code doesn't appear to call these vulnerabilities.", "."))
+			switch {
+			case !h.scanLevel.WantSymbols():
+				summary.WriteString(".")
+			case h.tools:
+				summary.WriteString(", but your build tools don't appear to call these vulnerabilities.")
+			default:
+				summary.WriteString(", but your code doesn't appear to call these vulnerabilities.")
+			}
 		}
 	}
 	return summary.String()
@@ -487,6 +1344,10 @@ func (h *TextHandler) summarySuggestion() string {
 			sugg.WriteString("Use " + verboseMessage + ".")
 		}
 	case govulncheck.ScanLevelPackage:
+		if h.symbolsFailed {
+			sugg.WriteString("The call graph of your code could not be built, so this scan was done at package level.")
+			break
+		}
 		sugg.WriteString("Use " + symbolMessage)
 		if !h.showVerbose {
 			sugg.WriteString(" and " + verboseMessage)
@@ -536,6 +1397,48 @@ func (h *TextHandler) print(values ...any) int {
 	return total
 }
 
+// advisoryURL returns the link to more information on e: template,
+// the -advisory-url-template, with the ID of e, if set, or else the
+// URL given by the database, or else the page of e on pkg.go.dev.
+func advisoryURL(template string, e *osv.Entry) string {
+	if template != "" {
+		return strings.ReplaceAll(template, advisoryIDPlaceholder, e.ID)
+	}
+	if e.DatabaseSpecific != nil && e.DatabaseSpecific.URL != "" {
+		return e.DatabaseSpecific.URL
+	}
+	return "https://pkg.go.dev/vuln/" + e.ID
+}
+
+// weaknesses returns the CWE IDs of the weaknesses
+// leading to the vulnerability of e, if known.
+func weaknesses(e *osv.Entry) []string {
+	if e.DatabaseSpecific == nil {
+		return nil
+	}
+	return e.DatabaseSpecific.CWEIDs
+}
+
+// fixLinks returns the URLs of the references of e
+// to the fix of the vulnerability, such as a commit.
+func fixLinks(e *osv.Entry) []string {
+	var urls []string
+	for _, r := range e.References {
+		if r.Type == osv.ReferenceTypeFix {
+			urls = append(urls, r.URL)
+		}
+	}
+	return urls
+}
+
+// lineWidth returns the width text is wrapped to.
+func (h *TextHandler) lineWidth() int {
+	if h.width > 0 {
+		return h.width
+	}
+	return defaultLineWidth
+}
+
 // wrap wraps s to fit in maxWidth by breaking it into lines at whitespace. If a
 // single word is longer than maxWidth, it is retained as its own line.
 func (h *TextHandler) wrap(indent string, s string, maxWidth int) {
@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestAdvisoryURL(t *testing.T) {
+	withURL := &osv.Entry{ID: "GO-2021-0113", DatabaseSpecific: &osv.DatabaseSpecific{URL: "https://pkg.go.dev/vuln/GO-2021-0113"}}
+	internal := &osv.Entry{ID: "SEC-0001"}
+	for _, test := range []struct {
+		template string
+		entry    *osv.Entry
+		want     string
+	}{
+		{"", withURL, "https://pkg.go.dev/vuln/GO-2021-0113"},
+		{"", internal, "https://pkg.go.dev/vuln/SEC-0001"},
+		{"https://sec.example.com/{id}", withURL, "https://sec.example.com/GO-2021-0113"},
+		{"https://sec.example.com/{id}", internal, "https://sec.example.com/SEC-0001"},
+	} {
+		if got := advisoryURL(test.template, test.entry); got != test.want {
+			t.Errorf("advisoryURL(%s) with template %q = %s, want %s", test.entry.ID, test.template, got, test.want)
+		}
+	}
+}
+
+func TestPanicTrace(t *testing.T) {
+	trace := []*govulncheck.Frame{
+		{Module: "golang.org/x/text", Package: "golang.org/x/text/language", Function: "Parse",
+			Position: &govulncheck.Position{Filename: "language/parse.go", Line: 33}},
+		{Module: "example.com/m", Package: "example.com/m/lib", Function: "Do", Receiver: "*T",
+			Position: &govulncheck.Position{Filename: "lib/lib.go", Line: 7}},
+		{Module: "example.com/m", Package: "example.com/m", Function: "main$1",
+			Position: &govulncheck.Position{Filename: "main.go", Line: 12}},
+		{Module: "example.com/m", Package: "example.com/m", Function: "main"},
+	}
+	var buf bytes.Buffer
+	h := NewTextHandler(&buf)
+	h.moduleDirs = map[string]string{"example.com/m": "/src/m"}
+	h.mainPkgs = map[string]bool{"example.com/m": true}
+	h.panicTrace(trace)
+	want := `goroutine 1 [running]:
+golang.org/x/text/language.Parse(...)
+	golang.org/x/text/language/parse.go:33 +0x0
+example.com/m/lib.(*T).Do(...)
+	/src/m/lib/lib.go:7 +0x0
+main.main.func1(...)
+	/src/m/main.go:12 +0x0
+main.main(...)
+	?:0 +0x0
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestConfigMainModules(t *testing.T) {
+	for _, test := range []struct {
+		mods    []string
+		verbose bool
+		want    string
+	}{
+		{nil, false, ""},
+		{[]string{"example.com/m"}, false, "Scanning module example.com/m...\n\n"},
+		{[]string{"example.com/a", "example.com/b"}, false, "Scanning modules example.com/a, example.com/b...\n\n"},
+		{[]string{"example.com/m"}, true, "Main module: example.com/m\n\n"},
+	} {
+		var buf bytes.Buffer
+		h := NewTextHandler(&buf)
+		h.showVerbose = test.verbose
+		if err := h.Config(&govulncheck.Config{MainModules: test.mods}); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("Config(%v) with verbose %t printed %q, want %q", test.mods, test.verbose, got, test.want)
+		}
+	}
+}
+
+func TestClearedByOverride(t *testing.T) {
+	entry := &osv.Entry{ID: "GO-0001", Affected: []osv.Affected{{
+		Module: osv.Module{Path: "golang.org/fork"},
+		Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.2.0"}}}},
+	}}}
+	// golang.org/orig is replaced by golang.org/fork, whose
+	// vulnerabilities are the ones that apply.
+	sbom := &govulncheck.SBOM{Modules: []*govulncheck.Module{
+		{Path: "golang.org/fork", Version: "v1.1.0", Replaces: "golang.org/orig"},
+	}}
+	const want = "\n=== Cleared by Override ===\n\nGO-0001: golang.org/fork@v1.1.0 (fixed locally at v1.1.0)\n"
+	for _, path := range []string{"golang.org/orig", "golang.org/fork"} {
+		var buf bytes.Buffer
+		h := NewTextHandler(&buf)
+		h.overrides = map[string]string{path: "v1.1.0"}
+		h.sbom = sbom
+		h.osvs = []*osv.Entry{entry}
+		h.clearedByOverride()
+		if diff := cmp.Diff(want, buf.String()); diff != "" {
+			t.Errorf("override of %s: mismatch (-want, +got):\n%s", path, diff)
+		}
+	}
+}
+
+func TestOverrideOf(t *testing.T) {
+	m := &govulncheck.Module{Path: "golang.org/fork", Version: "v1.1.0", Replaces: "golang.org/orig"}
+	for _, test := range []struct {
+		overrides map[string]string
+		path      string
+		fixed     string
+		ok        bool
+	}{
+		{nil, "golang.org/fork", "", false},
+		{map[string]string{"golang.org/fork": "v1.0.0"}, "golang.org/fork", "v1.0.0", true},
+		{map[string]string{"golang.org/orig": "v1.1.0"}, "golang.org/orig", "v1.1.0", true},
+		// The replaced module takes precedence.
+		{map[string]string{"golang.org/orig": "v1.1.0", "golang.org/fork": "v1.0.0"}, "golang.org/orig", "v1.1.0", true},
+	} {
+		path, fixed, ok := overrideOf(m, test.overrides)
+		if ok != test.ok || (ok && (path != test.path || fixed != test.fixed)) {
+			t.Errorf("overrideOf(%v) = %s, %s, %t, want %s, %s, %t", test.overrides, path, fixed, ok, test.path, test.fixed, test.ok)
+		}
+	}
+}
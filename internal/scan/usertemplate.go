@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// TemplateHandler renders govulncheck output through a user-supplied
+// text/template, see -template.
+type TemplateHandler struct {
+	w    io.Writer
+	tmpl *template.Template
+	data templateData
+
+	// failOn is the finding level at which vulnerabilities
+	// are reported as found. Defaults to the scan level.
+	failOn govulncheck.ScanLevel
+
+	// failOnModules overrides failOn for
+	// some packages of the scanned code, see -fail-on-modules.
+	failOnModules failLevels
+}
+
+// templateData is the value user templates are executed with.
+type templateData struct {
+	// Config describes the scan.
+	Config *govulncheck.Config
+	// SBOM lists the modules of the scanned code, if known.
+	SBOM *govulncheck.SBOM
+	// OSVs are the OSV entries of the modules scanned.
+	OSVs []*osv.Entry
+	// Findings are the vulnerability findings, in stream order.
+	Findings []*govulncheck.Finding
+	// SymbolsFailed is set when the call graph could not be built,
+	// and the scan level of Config is then lowered to package.
+	SymbolsFailed bool
+}
+
+// parseUserTemplate parses the template in the file at path. Besides
+// the text/template builtins, the template can use the functions
+//
+//	osv ID          the OSV entry with the given ID
+//	inc N           N+1, for 1-based numbering of range indices
+//	indent N S      S with each line indented by N spaces
+//	wrap N S        S word wrapped to lines of at most N characters
+func parseUserTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// The osv function is redefined for each handler, as it
+	// needs access to the OSV entries of the stream.
+	funcs := template.FuncMap{
+		"osv":    func(string) *osv.Entry { return nil },
+		"inc":    func(i int) int { return i + 1 },
+		"indent": indentLines,
+		"wrap":   wrapWords,
+	}
+	return template.New(filepath.Base(path)).Funcs(funcs).Parse(string(data))
+}
+
+// NewTemplateHandler returns a handler that writes govulncheck output
+// rendered by tmpl, which must be obtained from parseUserTemplate.
+func NewTemplateHandler(w io.Writer, tmpl *template.Template) *TemplateHandler {
+	h := &TemplateHandler{w: w}
+	h.tmpl = tmpl.Funcs(template.FuncMap{
+		"osv": func(id string) *osv.Entry { return getOSV(h.data.OSVs, id) },
+	})
+	return h
+}
+
+func (h *TemplateHandler) Config(c *govulncheck.Config) error {
+	h.data.Config = c
+	h.failOnModules = h.failOnModules.resolve(c.MainModules)
+	return nil
+}
+
+func (h *TemplateHandler) SBOM(s *govulncheck.SBOM) error {
+	h.data.SBOM = s
+	return nil
+}
+
+func (h *TemplateHandler) Progress(p *govulncheck.Progress) error {
+	if p.SymbolsFailed {
+		h.data.SymbolsFailed = true
+		if h.data.Config != nil {
+			lowered := *h.data.Config
+			lowered.ScanLevel = withoutSymbols(lowered.ScanLevel)
+			h.data.Config = &lowered
+		}
+		h.failOn = withoutSymbols(h.failOn)
+		h.failOnModules = h.failOnModules.withoutSymbols()
+	}
+	return nil // other progress is not needed by templates
+}
+
+func (h *TemplateHandler) Graph(g *govulncheck.Graph) error {
+	return nil // not needed by templates
+}
+
+func (h *TemplateHandler) Timing(t *govulncheck.Timing) error {
+	return nil // not needed by templates
+}
+
+func (h *TemplateHandler) OSV(e *osv.Entry) error {
+	h.data.OSVs = append(h.data.OSVs, e)
+	return nil
+}
+
+func (h *TemplateHandler) Finding(f *govulncheck.Finding) error {
+	if err := validateFindings(f); err != nil {
+		return err
+	}
+	h.data.Findings = append(h.data.Findings, f)
+	return nil
+}
+
+// Flush executes the template. Like text output, it reports
+// vulnerabilities found at the -fail-on level with an error.
+func (h *TemplateHandler) Flush() error {
+	if err := h.tmpl.Execute(h.w, &h.data); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	var findings []*findingSummary
+	for _, f := range h.data.Findings {
+		findings = append(findings, newFindingSummary(f))
+	}
+	level := h.failOn
+	if level == "" && h.data.Config != nil {
+		level = h.data.Config.ScanLevel
+	}
+	if vulnerabilitiesFound(findings, level, h.failOnModules) {
+		return errVulnerabilitiesFound
+	}
+	return nil
+}
+
+// indentLines prefixes each non-empty line of s with n spaces.
+func indentLines(n int, s string) string {
+	prefix := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapWords word wraps s to lines of at most width characters,
+// unless a single word is longer than that.
+func wrapWords(width int, s string) string {
+	var b strings.Builder
+	w := 0
+	for _, f := range strings.Fields(s) {
+		switch {
+		case w == 0:
+		case w+len(f)+1 > width:
+			b.WriteString("\n")
+			w = 0
+		default:
+			b.WriteString(" ")
+			w++
+		}
+		b.WriteString(f)
+		w += len(f)
+	}
+	return b.String()
+}
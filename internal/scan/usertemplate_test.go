@@ -0,0 +1,24 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import "testing"
+
+func TestTemplateFuncs(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"indent", indentLines(2, "a\n\nb c"), "  a\n\n  b c"},
+		{"wrap", wrapWords(7, "aa bb cc dd"), "aa bb\ncc dd"},
+		{"wrap long word", wrapWords(3, "aaaa b"), "aaaa\nb"},
+		{"wrap spaces", wrapWords(10, "  a\n b  "), "a b"},
+	} {
+		if test.got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, test.got, test.want)
+		}
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"golang.org/x/mod/modfile"
 	"golang.org/x/vuln/internal"
 	"golang.org/x/vuln/internal/govulncheck"
 )
@@ -49,12 +52,154 @@ func moduleVersionString(modulePath, version string) string {
 	return version
 }
 
-func gomodExists(dir string) bool {
-	cmd := exec.Command("go", "env", "GOMOD")
+// gomodExists reports whether dir is in a module,
+// or in a workspace of modules.
+func gomodExists(dir string, env []string) bool {
+	return gomodFile(dir, env) != "" || goworkFile(dir, env) != ""
+}
+
+// gomodFile returns the path of the go.mod file of the main
+// module in dir, or "" if there is none.
+func gomodFile(dir string, env []string) string {
+	return goEnvFile(dir, env, "GOMOD")
+}
+
+// gomodModulePath returns the path of the main module in dir,
+// or "" if there is none.
+func gomodModulePath(dir string, env []string) string {
+	path := gomodFile(dir, env)
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return modfile.ModulePath(data)
+}
+
+// goworkFile returns the path of the go.work file of the
+// workspace dir is in, or "" if there is none or workspaces
+// are turned off with GOWORK=off.
+func goworkFile(dir string, env []string) string {
+	return goEnvFile(dir, env, "GOWORK")
+}
+
+// goEnvFile returns the value of the go env variable name in dir,
+// which must be the path of a file, or "" if there is no such file.
+func goEnvFile(dir string, env []string, name string) string {
+	cmd := exec.Command("go", "env", name)
 	cmd.Dir = dir
+	cmd.Env = env
 	out, err := cmd.Output()
 	output := strings.TrimSpace(string(out))
 	// If module-aware mode is enabled, but there is no go.mod, GOMOD will be os.DevNull
 	// If module-aware mode is disabled, GOMOD will be the empty string.
-	return err == nil && !(output == os.DevNull || output == "")
+	// GOWORK is the empty string or "off" without a workspace.
+	if err != nil || output == os.DevNull || output == "off" {
+		return ""
+	}
+	return output
+}
+
+// gomodGoVersion returns the Go version the main modules in dir
+// are built with, as a Go tag like "go1.21.5". It is given by the
+// toolchain directive of the go.work file of the workspace, if any,
+// or else of the go.mod file, and by their go directive otherwise.
+// It returns "" if there is no such file or it specifies neither.
+func gomodGoVersion(dir string, env []string) string {
+	var toolchain *modfile.Toolchain
+	var goVersion *modfile.Go
+	if path := goworkFile(dir, env); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		f, err := modfile.ParseWork(path, data, nil)
+		if err != nil {
+			return ""
+		}
+		toolchain, goVersion = f.Toolchain, f.Go
+	} else if path := gomodFile(dir, env); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		f, err := modfile.Parse(path, data, nil)
+		if err != nil {
+			return ""
+		}
+		toolchain, goVersion = f.Toolchain, f.Go
+	}
+	if toolchain != nil && strings.HasPrefix(toolchain.Name, "go1") {
+		return toolchain.Name
+	}
+	if goVersion != nil {
+		return "go" + goVersion.Version
+	}
+	return ""
+}
+
+// workspacePatterns returns patterns with each "./..." pattern
+// replaced by patterns matching the packages of all modules of
+// the workspace in dir, if any, that are in dir or below it.
+// Unlike in a module, "./..." in a workspace only matches the
+// packages of the module in dir. Without patterns, as in module
+// level scans, all these modules are loaded when dir is not in
+// one of them.
+func workspacePatterns(dir string, env []string, patterns []string) []string {
+	all := len(patterns) == 0 && gomodFile(dir, env) == ""
+	if !all && !slices.Contains(patterns, "./...") {
+		return patterns
+	}
+	mods := workspaceModulePatterns(dir, env)
+	if len(mods) == 0 {
+		return patterns
+	}
+	if all {
+		return mods
+	}
+	var result []string
+	for _, p := range patterns {
+		if p == "./..." {
+			result = append(result, mods...)
+		} else {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// workspaceModulePatterns returns a pattern of the form "./dir/..."
+// for each module of the workspace in dir that is in dir or below it.
+func workspaceModulePatterns(dir string, env []string) []string {
+	path := goworkFile(dir, env)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	f, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	var patterns []string
+	for _, u := range f.Use {
+		mdir := u.Path
+		if !filepath.IsAbs(mdir) {
+			mdir = filepath.Join(filepath.Dir(path), mdir)
+		}
+		rel, err := filepath.Rel(abs, mdir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue // not in dir
+		}
+		patterns = append(patterns, "./"+filepath.ToSlash(filepath.Join(rel, "...")))
+	}
+	return patterns
 }
@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// warnf records a warning about the scan in cfg. Warnings found
+// before the handler is created, such as while parsing flags or
+// loading packages, are reported to it by emitWarnings, so that
+// they reach every output format.
+func (cfg *config) warnf(format string, args ...any) {
+	cfg.warnings = append(cfg.warnings, fmt.Sprintf("warning: "+format, args...))
+}
+
+// emitWarnings emits the warnings recorded in cfg as progress
+// messages to handler, and clears them.
+func emitWarnings(handler govulncheck.Handler, cfg *config) error {
+	for _, w := range cfg.warnings {
+		if err := handler.Progress(&govulncheck.Progress{Message: w}); err != nil {
+			return err
+		}
+	}
+	cfg.warnings = nil
+	return nil
+}
@@ -5,8 +5,11 @@
 package semver
 
 import (
+	"slices"
 	"sort"
+	"strings"
 
+	"golang.org/x/mod/module"
 	"golang.org/x/vuln/internal/osv"
 )
 
@@ -15,26 +18,172 @@ func Affects(a []osv.Range, v string) bool {
 		// No ranges implies all versions are affected
 		return true
 	}
-	var semverRangePresent bool
+	var versionRangePresent bool
 	for _, r := range a {
-		if r.Type != osv.RangeTypeSemver {
+		if !IsVersionRange(r) {
 			continue
 		}
-		semverRangePresent = true
+		versionRangePresent = true
 		if ContainsSemver(r, v) {
 			return true
 		}
 	}
-	// If there were no semver ranges present we
+	if versionRangePresent {
+		return false
+	}
+	// If there were no version ranges present we
 	// assume that all semvers are affected, similarly
 	// to how to we assume all semvers are affected
-	// if there are no ranges at all.
-	return !semverRangePresent
+	// if there are no ranges at all, unless git
+	// ranges tell otherwise.
+	for _, r := range a {
+		if r.Type != osv.RangeTypeGit {
+			return true // a range we cannot evaluate
+		}
+	}
+	affected, _ := affectsGit(a, v)
+	return affected
+}
+
+// AffectsBetween reports whether any version v with lo <= v <= hi
+// is affected by a, see AffectedBetween.
+func AffectsBetween(a []osv.Range, lo, hi string) bool {
+	return AffectedBetween(a, lo, hi) != ""
+}
+
+// AffectedBetween returns the lowest version v with lo <= v <= hi
+// that is affected by a, or "" if there is none. It is lo if lo is in
+// a version range, or else the lowest version introducing a
+// vulnerability after lo and at or before hi. If a has no version
+// ranges, AffectedBetween is hi if Affects(a, hi).
+func AffectedBetween(a []osv.Range, lo, hi string) string {
+	if Less(hi, lo) {
+		lo, hi = hi, lo
+	}
+	if !slices.ContainsFunc(a, IsVersionRange) {
+		if Affects(a, hi) {
+			return hi
+		}
+		return ""
+	}
+	if Affects(a, lo) {
+		return lo
+	}
+	var first string
+	for _, r := range a {
+		if !IsVersionRange(r) {
+			continue
+		}
+		for _, e := range r.Events {
+			if e.Introduced != "" && e.Introduced != "0" &&
+				Less(lo, e.Introduced) && !Less(hi, e.Introduced) &&
+				(first == "" || Less(e.Introduced, first)) {
+				first = e.Introduced
+			}
+		}
+	}
+	if first == "" {
+		return ""
+	}
+	return canonicalizeSemverPrefix(first)
+}
+
+// IsVersionRange reports whether the events of r are Go module
+// versions, that is whether r is a SEMVER range, or an ECOSYSTEM
+// range whose versions are all valid semantic versions. Such
+// ranges are evaluated alike.
+func IsVersionRange(r osv.Range) bool {
+	switch r.Type {
+	case osv.RangeTypeSemver:
+		return true
+	case osv.RangeTypeEcosystem:
+		for _, e := range r.Events {
+			for _, v := range []string{e.Introduced, e.Fixed} {
+				if v != "" && v != "0" && !Valid(v) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// UnknownRanges returns the types of the ranges in a that cannot be
+// evaluated for version v, in the order they first appear in a. These
+// are git commit ranges that do not mention the commit of v, ECOSYSTEM
+// ranges with versions that are not semantic versions, and ranges of
+// other types. They matter only when a has no range that IsVersionRange,
+// and Affects then conservatively reports v as affected.
+func UnknownRanges(a []osv.Range, v string) []osv.RangeType {
+	var types []osv.RangeType
+	for _, r := range a {
+		if IsVersionRange(r) {
+			return nil
+		}
+		if r.Type == osv.RangeTypeGit {
+			if _, known := affectsGit(a, v); known {
+				continue
+			}
+		}
+		if !slices.Contains(types, r.Type) {
+			types = append(types, r.Type)
+		}
+	}
+	return types
+}
+
+// affectsGit reports whether the git commit ranges in a affect
+// version v, and whether that could be determined. Without the
+// history of the repository, this is only possible if v is a
+// pseudo-version of commits named by the range events. If it
+// cannot be determined, v is conservatively reported as affected.
+func affectsGit(a []osv.Range, v string) (affected, known bool) {
+	var rev string
+	if v = canonicalizeSemverPrefix(v); module.IsPseudoVersion(v) {
+		rev, _ = module.PseudoVersionRev(v)
+	}
+	present := false
+	known = true
+	for _, r := range a {
+		if r.Type != osv.RangeTypeGit {
+			continue
+		}
+		present = true
+		contains, ok := containsGit(r, rev)
+		if ok && contains {
+			return true, true
+		}
+		known = known && ok
+	}
+	if !present || !known {
+		return true, false
+	}
+	return false, true
+}
+
+// containsGit reports whether the commit with the abbreviated
+// hash rev, as found in pseudo-versions, is in the git range ar,
+// and whether that is known. Without the history of the
+// repository, this is only known if rev is one of the events.
+func containsGit(ar osv.Range, rev string) (contains, known bool) {
+	if rev == "" {
+		return false, false
+	}
+	for _, e := range ar.Events {
+		switch {
+		case e.Introduced != "" && e.Introduced != "0" && strings.HasPrefix(e.Introduced, rev):
+			return true, true
+		case e.Fixed != "" && strings.HasPrefix(e.Fixed, rev):
+			return false, true
+		}
+	}
+	return false, false
 }
 
 // ContainsSemver checks if semver version v is in the
-// range encoded by ar. If ar is not a semver range,
-// returns false. A range is interpreted as a left-closed
+// range encoded by ar. If ar is not a version range,
+// see IsVersionRange, returns false. A range is interpreted as a left-closed
 // and right-open interval.
 //
 // Assumes that
@@ -44,7 +193,7 @@ func Affects(a []osv.Range, v string) bool {
 //   - no-fix is not an event, as opposed to being an
 //     event where Introduced="" and Fixed=""
 func ContainsSemver(ar osv.Range, v string) bool {
-	if ar.Type != osv.RangeTypeSemver {
+	if !IsVersionRange(ar) {
 		return false
 	}
 	if len(ar.Events) == 0 {
@@ -7,6 +7,7 @@ package semver
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/vuln/internal/osv"
 )
 
@@ -126,6 +127,60 @@ func TestAffectsSemver(t *testing.T) {
 			version: "go3.0.1",
 			want:    true,
 		},
+		{
+			// +incompatible is build metadata, v2.0.0+incompatible is v2.0.0
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "2.0.0"}}}},
+			version: "v2.0.0+incompatible",
+			want:    false,
+		},
+		{
+			// +incompatible fixed version
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "2.0.0+incompatible"}}}},
+			version: "v2.1.0+incompatible",
+			want:    false,
+		},
+		{
+			// v1.9.0 < v2.0.0+incompatible
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "2.0.0+incompatible"}}}},
+			version: "v1.9.0",
+			want:    true,
+		},
+		{
+			// v2.0.0+incompatible <= v2.0.5+incompatible < v2.1.0+incompatible
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "2.0.0+incompatible"}, {Fixed: "2.1.0+incompatible"}}}},
+			version: "v2.0.5+incompatible",
+			want:    true,
+		},
+		{
+			// Pseudo-version of a commit after the fixed v1.2.3 tag
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.2.3"}}}},
+			version: "v1.2.4-0.20220101000000-abcdefabcdef",
+			want:    false,
+		},
+		{
+			// Pseudo-version of a commit before the fixed v1.2.3 tag
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.2.3"}}}},
+			version: "v1.2.3-0.20220101000000-abcdefabcdef",
+			want:    true,
+		},
+		{
+			// Pseudo-version at the fixed pseudo-version
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "0.0.0-20220101000000-abcdefabcdef"}}}},
+			version: "v0.0.0-20220101000000-abcdefabcdef",
+			want:    false,
+		},
+		{
+			// Pseudo-version after the fixed pseudo-version
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "0.0.0-20220101000000-abcdefabcdef"}}}},
+			version: "v0.0.0-20230101000000-123456123456",
+			want:    false,
+		},
+		{
+			// +incompatible pseudo-version of a commit after the fixed v2.0.0 tag
+			affects: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "2.0.0"}}}},
+			version: "v2.0.1-0.20220101000000-abcdefabcdef+incompatible",
+			want:    false,
+		},
 	}
 
 	for _, c := range cases {
@@ -135,3 +190,136 @@ func TestAffectsSemver(t *testing.T) {
 		}
 	}
 }
+
+func TestAffectsGit(t *testing.T) {
+	const (
+		introduced = "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"
+		fixed      = "0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e"
+	)
+	git := []osv.Range{{Type: osv.RangeTypeGit, Events: []osv.RangeEvent{{Introduced: introduced}, {Fixed: fixed}}}}
+	for _, test := range []struct {
+		affects []osv.Range
+		version string
+		want    bool
+		unknown bool
+	}{
+		// pseudo-version of the introducing commit
+		{git, "v0.0.0-20230101000000-1a2b3c4d5e6f", true, false},
+		// pseudo-version of the fixing commit
+		{git, "v1.2.1-0.20230201000000-0f9e8d7c6b5a", false, false},
+		// pseudo-version of another commit
+		{git, "v0.0.0-20230115000000-abcdefabcdef", true, true},
+		// not a pseudo-version
+		{git, "v1.2.0", true, true},
+		// semver ranges take precedence
+		{append([]osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.0.0"}}}}, git...), "v1.2.0", false, false},
+		// no ranges
+		{nil, "v1.2.0", true, false},
+	} {
+		if got := Affects(test.affects, test.version); got != test.want {
+			t.Errorf("Affects(%v, %s) = %t, want %t", test.affects, test.version, got, test.want)
+		}
+		if got := len(UnknownRanges(test.affects, test.version)) > 0; got != test.unknown {
+			t.Errorf("UnknownRanges(%v, %s) = %t, want %t", test.affects, test.version, got, test.unknown)
+		}
+	}
+}
+
+func TestAffectsEcosystem(t *testing.T) {
+	ecosystem := func(events ...osv.RangeEvent) osv.Range {
+		return osv.Range{Type: osv.RangeTypeEcosystem, Events: events}
+	}
+	modVersions := ecosystem(osv.RangeEvent{Introduced: "1.1.0"}, osv.RangeEvent{Fixed: "1.3.0"})
+	dates := ecosystem(osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "2024-01-01"})
+	semverRange := osv.Range{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "2.0.0"}}}
+	for _, test := range []struct {
+		affects []osv.Range
+		version string
+		want    bool
+		unknown []osv.RangeType
+	}{
+		// ECOSYSTEM ranges of module versions are evaluated like SEMVER ranges
+		{[]osv.Range{modVersions}, "v1.2.0", true, nil},
+		{[]osv.Range{modVersions}, "v1.3.0", false, nil},
+		{[]osv.Range{modVersions}, "v1.0.0", false, nil},
+		// together with SEMVER ranges
+		{[]osv.Range{modVersions, semverRange}, "v2.1.0", true, nil},
+		{[]osv.Range{modVersions, semverRange}, "v1.5.0", false, nil},
+		// other ECOSYSTEM ranges are assumed to contain all versions
+		{[]osv.Range{dates}, "v1.5.0", true, []osv.RangeType{osv.RangeTypeEcosystem}},
+		// unless there are version ranges
+		{[]osv.Range{dates, modVersions}, "v1.5.0", false, nil},
+		{[]osv.Range{dates, semverRange}, "v1.5.0", false, nil},
+		// ranges of unknown types are assumed to contain all versions too
+		{[]osv.Range{{Type: "unspecified"}, dates}, "v1.5.0", true, []osv.RangeType{"unspecified", osv.RangeTypeEcosystem}},
+	} {
+		if got := Affects(test.affects, test.version); got != test.want {
+			t.Errorf("Affects(%v, %s) = %t, want %t", test.affects, test.version, got, test.want)
+		}
+		if diff := cmp.Diff(test.unknown, UnknownRanges(test.affects, test.version)); diff != "" {
+			t.Errorf("UnknownRanges(%v, %s) mismatch (-want, +got):\n%s", test.affects, test.version, diff)
+		}
+	}
+}
+
+func TestAffectedBetween(t *testing.T) {
+	semverRange := func(events ...osv.RangeEvent) []osv.Range {
+		return []osv.Range{{Type: osv.RangeTypeSemver, Events: events}}
+	}
+	cases := []struct {
+		affects []osv.Range
+		lo, hi  string
+		want    string // lowest affected version
+	}{
+		{
+			// fixed before lo
+			affects: semverRange(osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.19.0"}),
+			lo:      "v1.19.0", hi: "v1.22.0",
+			want: "",
+		},
+		{
+			// lo affected, hi not
+			affects: semverRange(osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.20.0"}),
+			lo:      "v1.19.0", hi: "v1.22.0",
+			want: "v1.19.0",
+		},
+		{
+			// introduced between lo and hi
+			affects: semverRange(osv.RangeEvent{Introduced: "1.21.0"}, osv.RangeEvent{Fixed: "1.21.5"}),
+			lo:      "v1.19.0", hi: "v1.22.0",
+			want: "v1.21.0",
+		},
+		{
+			// introduced at hi
+			affects: semverRange(osv.RangeEvent{Introduced: "1.22.0"}),
+			lo:      "v1.19.0", hi: "v1.22.0",
+			want: "v1.22.0",
+		},
+		{
+			// introduced after hi
+			affects: semverRange(osv.RangeEvent{Introduced: "1.23.0"}),
+			lo:      "v1.19.0", hi: "v1.22.0",
+			want: "",
+		},
+		{
+			// bounds in either order
+			affects: semverRange(osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.20.0"}),
+			lo:      "v1.22.0", hi: "v1.19.0",
+			want: "v1.19.0",
+		},
+		{
+			// no version ranges
+			affects: nil,
+			lo:      "v1.19.0", hi: "v1.22.0",
+			want: "v1.22.0",
+		},
+	}
+	for _, c := range cases {
+		if got := AffectedBetween(c.affects, c.lo, c.hi); got != c.want {
+			t.Errorf("%#v.AffectedBetween(%s, %s): want %q, got %q", c.affects, c.lo, c.hi, c.want, got)
+		}
+		if got := AffectsBetween(c.affects, c.lo, c.hi); got != (c.want != "") {
+			t.Errorf("%#v.AffectsBetween(%s, %s): want %t, got %t", c.affects, c.lo, c.hi, c.want != "", got)
+		}
+	}
+}
@@ -13,7 +13,7 @@ import "golang.org/x/vuln/internal/osv"
 func NonSupersededFix(ranges []osv.Range) string {
 	var latestFixed string
 	for _, r := range ranges {
-		if r.Type == "SEMVER" {
+		if IsVersionRange(r) {
 			for _, e := range r.Events {
 				fixed := e.Fixed
 				if fixed != "" && Less(latestFixed, fixed) {
@@ -43,7 +43,33 @@ func canonicalizeSemverPrefix(s string) string {
 // Less returns whether v1 < v2, where v1 and v2 are
 // semver versions with either a "v", "go" or no prefix.
 func Less(v1, v2 string) bool {
-	return semver.Compare(canonicalizeSemverPrefix(v1), canonicalizeSemverPrefix(v2)) < 0
+	return Compare(v1, v2) < 0
+}
+
+// Compare returns -1, 0 or +1 depending on whether v1 < v2, v1 == v2
+// or v1 > v2, where v1 and v2 are semver versions with either a "v",
+// "go" or no prefix. An invalid version is less than any valid one,
+// and two invalid versions are equal.
+func Compare(v1, v2 string) int {
+	return semver.Compare(canonicalizeSemverPrefix(v1), canonicalizeSemverPrefix(v2))
+}
+
+// Incompatible returns fixed with an +incompatible suffix if it is a
+// fix for a module at +incompatible version v. Such a module has no
+// major version suffix in its path, so its versions at major version
+// 2 and above are +incompatible.
+func Incompatible(fixed, v string) string {
+	if !strings.HasSuffix(v, "+incompatible") {
+		return fixed
+	}
+	c := canonicalizeSemverPrefix(fixed)
+	if !semver.IsValid(c) || semver.Build(c) != "" {
+		return fixed
+	}
+	if m := semver.Major(c); m == "v0" || m == "v1" {
+		return fixed
+	}
+	return fixed + "+incompatible"
 }
 
 // Valid returns whether v is valid semver, allowing
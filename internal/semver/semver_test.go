@@ -78,3 +78,40 @@ func TestLess(t *testing.T) {
 		}
 	}
 }
+
+func TestCompare(t *testing.T) {
+	for _, test := range []struct {
+		v1   string
+		v2   string
+		want int
+	}{
+		{"v1.9.0", "v1.10.0", -1},
+		{"v1.10.0", "v1.9.0", 1},
+		{"go1.21", "v1.21.0", 0},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"", "v0.0.1", -1},
+		{"", "", 0},
+	} {
+		if got := Compare(test.v1, test.v2); got != test.want {
+			t.Errorf("want Compare(%s, %s)=%d; got %d", test.v1, test.v2, test.want, got)
+		}
+	}
+}
+
+func TestIncompatible(t *testing.T) {
+	for _, test := range []struct {
+		fixed, v string
+		want     string
+	}{
+		{"v2.1.0", "v2.0.0+incompatible", "v2.1.0+incompatible"},
+		{"v2.1.0+incompatible", "v2.0.0+incompatible", "v2.1.0+incompatible"},
+		{"v1.5.0", "v1.0.0", "v1.5.0"},
+		{"v1.5.0", "v2.0.0+incompatible", "v1.5.0"},
+		{"v2.1.0", "v2.0.0", "v2.1.0"},
+		{"", "v2.0.0+incompatible", ""},
+	} {
+		if got := Incompatible(test.fixed, test.v); got != test.want {
+			t.Errorf("Incompatible(%s, %s) = %s, want %s", test.fixed, test.v, got, test.want)
+		}
+	}
+}
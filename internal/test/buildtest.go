@@ -27,6 +27,17 @@ var unsupportedGoosGoarch = map[string]bool{
 // It returns the path to the resulting binary, and a function
 // to call when finished with the binary.
 func GoBuild(t *testing.T, dir, tags string, strip bool, envVarVals ...string) (binaryPath string, cleanup func()) {
+	return goBuild(t, dir, false, tags, strip, envVarVals...)
+}
+
+// GoTestBuild runs "go test -c" on dir to build a test binary for
+// the package in dir. It returns the path to the resulting binary,
+// and a function to call when finished with the binary.
+func GoTestBuild(t *testing.T, dir string) (binaryPath string, cleanup func()) {
+	return goBuild(t, dir, true, "", false)
+}
+
+func goBuild(t *testing.T, dir string, test bool, tags string, strip bool, envVarVals ...string) (binaryPath string, cleanup func()) {
 	testenv.NeedsGoBuild(t)
 
 	if len(envVarVals)%2 != 0 {
@@ -54,6 +65,9 @@ func GoBuild(t *testing.T, dir, tags string, strip bool, envVarVals ...string) (
 		t.Fatal(err)
 	}
 	binaryPath = filepath.Join(tmpDir, filepath.Base(abs))
+	if test {
+		binaryPath += ".test"
+	}
 	var exeSuffix string
 	if runtime.GOOS == "windows" {
 		exeSuffix = ".exe"
@@ -64,6 +78,9 @@ func GoBuild(t *testing.T, dir, tags string, strip bool, envVarVals ...string) (
 		t.Fatal(err)
 	}
 	args := []string{"build", "-o", binaryPath + exeSuffix}
+	if test {
+		args = []string{"test", "-c", "-o", binaryPath + exeSuffix}
+	}
 	if tags != "" {
 		args = append(args, "-tags", tags)
 	}
@@ -21,6 +21,8 @@ type MockHandler struct {
 	ProgressMessages []*govulncheck.Progress
 	OSVMessages      []*osv.Entry
 	FindingMessages  []*govulncheck.Finding
+	GraphMessages    []*govulncheck.Graph
+	TimingMessages   []*govulncheck.Timing
 }
 
 func NewMockHandler() *MockHandler {
@@ -52,6 +54,16 @@ func (h *MockHandler) Finding(finding *govulncheck.Finding) error {
 	return nil
 }
 
+func (h *MockHandler) Graph(graph *govulncheck.Graph) error {
+	h.GraphMessages = append(h.GraphMessages, graph)
+	return nil
+}
+
+func (h *MockHandler) Timing(timing *govulncheck.Timing) error {
+	h.TimingMessages = append(h.TimingMessages, timing)
+	return nil
+}
+
 func (h *MockHandler) Sort() {
 	sort.Slice(h.FindingMessages, func(i, j int) bool {
 		if h.FindingMessages[i].OSV > h.FindingMessages[j].OSV {
@@ -126,5 +138,15 @@ func (h *MockHandler) Write(to govulncheck.Handler) error {
 			}
 		}
 	}
+	for _, graph := range h.GraphMessages {
+		if err := to.Graph(graph); err != nil {
+			return err
+		}
+	}
+	for _, timing := range h.TimingMessages {
+		if err := to.Timing(timing); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -6,7 +6,9 @@ package vulncheck
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal"
@@ -33,16 +35,55 @@ type Bin struct {
 // Binary detects presence of vulnerable symbols in bin and
 // emits findings to handler.
 func Binary(ctx context.Context, handler govulncheck.Handler, bin *Bin, cfg *govulncheck.Config, client *client.Client) error {
+	if err := checkBin(bin); err != nil {
+		return err
+	}
 	vr, err := binary(ctx, handler, bin, cfg, client)
 	if err != nil {
 		return err
 	}
+	// Without a call graph, the vulnerable symbols
+	// are only known to be present in the binary.
 	if cfg.ScanLevel.WantSymbols() {
-		return emitCallFindings(handler, binaryCallstacks(vr))
+		return emitCallFindings(handler, binaryCallstacks(vr), nil, false, govulncheck.ReachabilityPresent)
+	}
+	return nil
+}
+
+// checkBin returns an error if bin has neither module information
+// nor a Go version, so that nothing in it can be checked. Binaries
+// with only a Go version are checked for standard library
+// vulnerabilities, with a warning, see emitBinWarnings.
+// It is done by Binary, rather than by runBinary in package scan, so
+// that it applies to all scans of binaries.
+func checkBin(bin *Bin) error {
+	if bin.Main == nil && len(bin.Modules) == 0 && !semver.Valid(bin.GoVersion) {
+		return errors.New("binary contains no module information and no Go version, cannot scan\n\n" +
+			"Scan the source code of the binary instead.")
 	}
 	return nil
 }
 
+// emitBinWarnings emits warnings for binaries that are only
+// checked for standard library vulnerabilities, because they
+// have no module information.
+func emitBinWarnings(handler govulncheck.Handler, bin *Bin) error {
+	var msg string
+	switch {
+	case semver.Valid(bin.GoVersion) && semver.Less(bin.GoVersion, "go1.18"):
+		// Binaries built before Go 1.18 have no module information.
+		msg = fmt.Sprintf("warning: binary built with Go version %s, only standard library vulnerabilities will be checked; rebuild the binary with Go 1.18 or later to check its modules", bin.GoVersion)
+	case bin.Main == nil && len(bin.Modules) == 0:
+		// This is, for instance, the case for test binaries of
+		// non-main packages built with "go test -c"
+		// (see https://go.dev/issue/33976).
+		msg = "warning: binary contains no module information, only standard library vulnerabilities will be checked; scan the source code of the binary to check its modules"
+	default:
+		return nil
+	}
+	return handler.Progress(&govulncheck.Progress{Message: msg})
+}
+
 // binary detects presence of vulnerable symbols in bin.
 // It does not compute call graphs so the corresponding
 // info in Result will be empty.
@@ -64,36 +105,43 @@ func binary(ctx context.Context, handler govulncheck.Handler, bin *Bin, cfg *gov
 		return nil, err
 	}
 
-	mv, err := FetchVulnerabilities(ctx, client, mods)
+	start := time.Now()
+	mv, skipped, err := FetchVulnerabilities(ctx, client, skipModules(mods, cfg.SkipModules))
 	if err != nil {
 		return nil, err
 	}
+	if err := emitSkippedNonGo(handler, skipped); err != nil {
+		return nil, err
+	}
+	if err := EmitTiming(handler, cfg, "fetch vulnerabilities", time.Since(start)); err != nil {
+		return nil, err
+	}
 
 	// Emit OSV entries immediately in their raw unfiltered form.
 	if err := emitOSVs(handler, mv); err != nil {
 		return nil, err
 	}
+	if err := emitLocalReplaceWarnings(handler, mv); err != nil {
+		return nil, err
+	}
+	if err := emitRangeWarnings(handler, mv); err != nil {
+		return nil, err
+	}
 
 	if err := handler.Progress(&govulncheck.Progress{Message: checkingBinVulnsMessage}); err != nil {
 		return nil, err
 	}
 
-	// Emit warning message for ancient Go binaries, defined as binaries
-	// built with Go version without support for debug.BuildInfo (< go1.18).
-	if semver.Valid(bin.GoVersion) && semver.Less(bin.GoVersion, "go1.18") {
-		p := &govulncheck.Progress{Message: fmt.Sprintf("warning: binary built with Go version %s, only standard library vulnerabilities will be checked", bin.GoVersion)}
-		if err := handler.Progress(p); err != nil {
-			return nil, err
-		}
+	if err := emitBinWarnings(handler, bin); err != nil {
+		return nil, err
 	}
-
 	if bin.GOOS == "" || bin.GOARCH == "" {
 		p := &govulncheck.Progress{Message: fmt.Sprintf("warning: failed to extract build system specification GOOS: %s GOARCH: %s\n", bin.GOOS, bin.GOARCH)}
 		if err := handler.Progress(p); err != nil {
 			return nil, err
 		}
 	}
-	affVulns := affectingVulnerabilities(mv, bin.GOOS, bin.GOARCH)
+	affVulns := affectingVulnerabilities(mv, bin.GOOS, bin.GOARCH, cfg.Overrides, cfg.AssumedVersions, cfg.MinGoVersion)
 	if err := emitModuleFindings(handler, affVulns); err != nil {
 		return nil, err
 	}
@@ -116,7 +164,7 @@ func binary(ctx context.Context, handler govulncheck.Handler, bin *Bin, cfg *gov
 	impVulns := binImportedVulnPackages(graph, pkgSymbols, affVulns)
 	// Emit information on imported vulnerable packages now to
 	// mimic behavior of source.
-	if err := emitPackageFindings(handler, impVulns); err != nil {
+	if err := emitPackageFindings(handler, impVulns, nil); err != nil {
 		return nil, err
 	}
 
@@ -130,20 +178,40 @@ func binary(ctx context.Context, handler govulncheck.Handler, bin *Bin, cfg *gov
 	return &Result{Vulns: symVulns}, nil
 }
 
+// packagesAndSymbols groups the symbols of bin by the path of
+// their package, so that every finding reports the package of its
+// symbol. Symbols without a package, such as compiler generated
+// ones, cannot be vulnerable and are skipped.
 func packagesAndSymbols(bin *Bin) map[string][]string {
 	pkgSymbols := make(map[string][]string)
 	for _, sym := range bin.PkgSymbols {
-		// If the name of the package is main, we need to expand
-		// it to its full path as that is what vuln db uses.
-		if sym.Pkg == "main" && bin.Path != "" {
-			pkgSymbols[bin.Path] = append(pkgSymbols[bin.Path], sym.Name)
-		} else {
-			pkgSymbols[sym.Pkg] = append(pkgSymbols[sym.Pkg], sym.Name)
+		pkg := sym.Pkg
+		if pkg == "main" {
+			// If the name of the package is main, we need to expand
+			// it to its full path as that is what vuln db uses.
+			pkg = mainPackagePath(bin)
+		}
+		if pkg == "" {
+			continue
 		}
+		pkgSymbols[pkg] = append(pkgSymbols[pkg], sym.Name)
 	}
 	return pkgSymbols
 }
 
+// mainPackagePath returns the path of the main package of bin.
+// Blobs and some binaries do not record it, in which case the
+// main module path is the best approximation.
+func mainPackagePath(bin *Bin) string {
+	if bin.Path != "" {
+		return bin.Path
+	}
+	if bin.Main != nil && bin.Main.Path != "" {
+		return bin.Main.Path
+	}
+	return "main"
+}
+
 func binImportedVulnPackages(graph *PackageGraph, pkgSymbols map[string][]string, affVulns affectingVulns) []*Vuln {
 	var vulns []*Vuln
 	for pkg := range pkgSymbols {
@@ -151,6 +219,7 @@ func binImportedVulnPackages(graph *PackageGraph, pkgSymbols map[string][]string
 			vuln := &Vuln{
 				OSV:     osv,
 				Package: graph.GetPackage(pkg),
+				Version: affVulns.version(internal.UnknownModulePath, pkg, osv.ID),
 			}
 			vulns = append(vulns, vuln)
 		}
@@ -167,6 +236,7 @@ func binVulnSymbols(graph *PackageGraph, pkgSymbols map[string][]string, affVuln
 					OSV:     osv,
 					Symbol:  symbol,
 					Package: graph.GetPackage(pkg),
+					Version: affVulns.version(internal.UnknownModulePath, pkg, osv.ID),
 				}
 				vulns = append(vulns, vuln)
 			}
@@ -218,13 +288,7 @@ func (bin *Bin) SBOM() (sbom *govulncheck.SBOM) {
 
 	sbom.GoVersion = bin.GoVersion
 	for _, mod := range bin.Modules {
-		if mod.Replace != nil {
-			mod = mod.Replace
-		}
-		sbom.Modules = append(sbom.Modules, &govulncheck.Module{
-			Path:    mod.Path,
-			Version: mod.Version,
-		})
+		sbom.Modules = append(sbom.Modules, sbomModule(mod))
 	}
 
 	// add stdlib to mirror source mode output
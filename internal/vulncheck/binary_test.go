@@ -12,7 +12,9 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal/buildinfo"
+	"golang.org/x/vuln/internal/client"
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
 	"golang.org/x/vuln/internal/test"
 )
 
@@ -87,3 +89,166 @@ func TestBinary(t *testing.T) {
 		t.Errorf("(-want, +got): %s", diff)
 	}
 }
+
+// TestBinaryPlatform checks that platform specific vulnerabilities
+// are reported only for binaries built for an affected platform.
+func TestBinaryPlatform(t *testing.T) {
+	c, err := client.NewInMemoryClient([]*osv.Entry{{
+		ID: "VW",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "golang.org/bmod"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver}},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{
+					Path:    "golang.org/bmod/bvuln",
+					GOOS:    []string{"windows"},
+					Symbols: []string{"Vuln"},
+				}},
+			},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		goos, goarch string
+		want         int
+	}{
+		{"windows", "amd64", 1},
+		{"linux", "amd64", 0},
+	} {
+		t.Run(tc.goos+"-"+tc.goarch, func(t *testing.T) {
+			bin := &Bin{
+				Modules:    []*packages.Module{{Path: "golang.org/bmod", Version: "v0.5.0"}},
+				GoVersion:  "go1.20",
+				GOOS:       tc.goos,
+				GOARCH:     tc.goarch,
+				PkgSymbols: []buildinfo.Symbol{{Pkg: "golang.org/bmod/bvuln", Name: "Vuln"}},
+			}
+			cfg := &govulncheck.Config{ScanLevel: "symbol"}
+			res, err := binary(context.Background(), test.NewMockHandler(), bin, cfg, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(res.Vulns); got != tc.want {
+				t.Errorf("got %d vulnerabilities, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestBinaryFindingPackages checks that every binary finding
+// reports the package of its vulnerable symbol.
+func TestBinaryFindingPackages(t *testing.T) {
+	c, err := client.NewInMemoryClient([]*osv.Entry{{
+		ID: "VM",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "golang.org/main"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver}},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{Path: "golang.org/main", Symbols: []string{"Vuln"}}},
+			},
+		}},
+	}, {
+		ID: "VB",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "golang.org/bmod"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver}},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{Path: "golang.org/bmod/bvuln"}},
+			},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		syms []buildinfo.Symbol
+		want []*govulncheck.Frame
+	}{
+		{
+			// The blob does not record the path of the main package.
+			name: "main package",
+			syms: []buildinfo.Symbol{{Pkg: "main", Name: "Vuln"}, {Pkg: "", Name: "go:buildid"}},
+			want: []*govulncheck.Frame{
+				{Module: "golang.org/bmod", Version: "v0.5.0", Package: "golang.org/bmod/bvuln", Function: "Other"},
+				{Module: "golang.org/main", Version: "v1.0.0", Package: "golang.org/main", Function: "Vuln"},
+			},
+		},
+		{
+			name: "stripped",
+			// Without symbols, each vulnerable package is reported
+			// with a placeholder symbol.
+			want: []*govulncheck.Frame{
+				{Module: "golang.org/bmod", Version: "v0.5.0", Package: "golang.org/bmod/bvuln", Function: "golang.org/bmod/bvuln/*"},
+				{Module: "golang.org/main", Version: "v1.0.0", Package: "golang.org/main", Function: "Vuln"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bin := &Bin{
+				Main:       &packages.Module{Path: "golang.org/main", Version: "v1.0.0"},
+				Modules:    []*packages.Module{{Path: "golang.org/bmod", Version: "v0.5.0"}},
+				GoVersion:  "go1.20",
+				GOOS:       "linux",
+				GOARCH:     "amd64",
+				PkgSymbols: tc.syms,
+			}
+			if tc.syms != nil {
+				bin.PkgSymbols = append(bin.PkgSymbols, buildinfo.Symbol{Pkg: "golang.org/bmod/bvuln", Name: "Other"})
+			}
+			h := test.NewMockHandler()
+			cfg := &govulncheck.Config{ScanLevel: "symbol"}
+			if err := Binary(context.Background(), h, bin, cfg, c); err != nil {
+				t.Fatal(err)
+			}
+			var got []*govulncheck.Frame
+			for _, f := range h.FindingMessages {
+				if f.Trace[0].Function != "" {
+					got = append(got, f.Trace[0])
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("symbol findings mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckBin(t *testing.T) {
+	mods := []*packages.Module{{Path: "golang.org/x/text", Version: "v0.3.0"}}
+	main := &packages.Module{Path: "example.com/m"}
+	syms := []buildinfo.Symbol{{Pkg: "net/http", Name: "Get"}}
+	for _, tc := range []struct {
+		name string
+		bin  *Bin
+		ok   bool
+		warn bool // only the standard library is checked
+	}{
+		{"modules", &Bin{GoVersion: "go1.21.0", Main: main, Modules: mods}, true, false},
+		{"main only", &Bin{GoVersion: "go1.21.0", Main: main}, true, false},
+		{"test binary", &Bin{GoVersion: "go1.21.0", PkgSymbols: syms}, true, true},
+		{"old go", &Bin{GoVersion: "go1.12.10", PkgSymbols: syms}, true, true},
+		{"no modules", &Bin{GoVersion: "go1.21.0"}, true, true},
+		{"nothing", &Bin{PkgSymbols: syms}, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkBin(tc.bin); (err == nil) != tc.ok {
+				t.Errorf("checkBin() = %v, want ok = %t", err, tc.ok)
+			}
+			if !tc.ok {
+				return
+			}
+			handler := test.NewMockHandler()
+			if err := emitBinWarnings(handler, tc.bin); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(handler.ProgressMessages) > 0; got != tc.warn {
+				t.Errorf("warned = %t, want %t", got, tc.warn)
+			}
+		})
+	}
+}
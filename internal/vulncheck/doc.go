@@ -38,10 +38,11 @@ The results of vulncheck are slices of the call graph, package imports graph,
 and module requires graph leading to the use of an identified vulnerability.
 The parts of these graphs not related to any vulnerabilities are omitted.
 
-The [CallStacks] and [ImportChains] functions search the returned slices for
-user-friendly representative call stacks and import chains. These call stacks
-and import chains are provided as examples of vulnerability uses in the client
-code.
+The emitted findings carry user-friendly representative witnesses found in
+these slices: symbol level findings carry a shortest call stack in their trace,
+and package level source findings a shortest import chain from a root package
+in their import chain. They are provided as examples of vulnerability uses in
+the client code.
 
 # Limitations
 
@@ -5,76 +5,272 @@
 package vulncheck
 
 import (
+	"fmt"
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/semver"
 )
 
-// emitOSVs emits all OSV vuln entries in modVulns to handler.
+// emitOSVs emits all OSV vuln entries in modVulns to handler,
+// sorted by ID. Entries fetched for several modules are emitted
+// once. The entries are not filtered by module version or platform,
+// so handlers see every entry consulted during the analysis.
 func emitOSVs(handler govulncheck.Handler, modVulns []*ModVulns) error {
+	var entries []*osv.Entry
 	for _, mv := range modVulns {
-		for _, v := range mv.Vulns {
-			if err := handler.OSV(v); err != nil {
-				return err
+		entries = append(entries, mv.Vulns...)
+	}
+	slices.SortStableFunc(entries, func(a, b *osv.Entry) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	entries = slices.CompactFunc(entries, func(a, b *osv.Entry) bool {
+		return a.ID == b.ID
+	})
+	for _, e := range entries {
+		if err := handler.OSV(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitLocalReplaceWarnings emits a warning for each module in modVulns
+// that is replaced by a local directory. Such modules have no version,
+// so their vulnerabilities cannot be checked.
+func emitLocalReplaceWarnings(handler govulncheck.Handler, modVulns []*ModVulns) error {
+	for _, mv := range modVulns {
+		if !isLocalReplace(mv.Module) {
+			continue
+		}
+		vulns := "vulnerabilities"
+		if len(mv.Vulns) == 1 {
+			vulns = "vulnerability"
+		}
+		msg := fmt.Sprintf("warning: skipping %d known %s of %s, which is replaced by the unversioned local directory %s",
+			len(mv.Vulns), vulns, mv.Module.Path, mv.Module.Replace.Path)
+		if err := handler.Progress(&govulncheck.Progress{Message: msg}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitNoEntriesWarning emits a warning that the analyzed packages have
+// no entry functions, so no calls from them can be found, and that the
+// build tags, if any, might be excluding their code.
+func emitNoEntriesWarning(handler govulncheck.Handler, tags []string) error {
+	msg := "warning: the scanned packages have no entry functions, so calls from them cannot be found"
+	if len(tags) > 0 {
+		msg = fmt.Sprintf("warning: the scanned packages have no entry functions with build tags %s, so calls from them cannot be found; the tags might exclude the files declaring them",
+			strings.Join(tags, ","))
+	}
+	return handler.Progress(&govulncheck.Progress{Message: msg})
+}
+
+// emitRangeWarnings emits a warning for each entry in modVulns with
+// ranges that could not be evaluated against the version of the
+// module, such as git commit ranges or ECOSYSTEM ranges of versions
+// that are not semantic versions. Such entries are assumed to affect
+// the module.
+func emitRangeWarnings(handler govulncheck.Handler, modVulns []*ModVulns) error {
+	for _, mv := range modVulns {
+		path, version := modPath(mv.Module), modVersion(mv.Module)
+		if version == "" {
+			continue
+		}
+		for _, e := range mv.Vulns {
+			for _, a := range e.Affected {
+				if a.Module.Path != path {
+					continue
+				}
+				for _, typ := range semver.UnknownRanges(a.Ranges, version) {
+					ranges := fmt.Sprintf("%s ranges", typ)
+					if typ == osv.RangeTypeGit {
+						ranges = "git commit ranges"
+					}
+					msg := fmt.Sprintf("warning: could not evaluate the %s of %s for %s@%s, assuming it is affected",
+						ranges, e.ID, path, version)
+					if err := handler.Progress(&govulncheck.Progress{Message: msg}); err != nil {
+						return err
+					}
+				}
 			}
 		}
 	}
 	return nil
 }
 
+// EmitTiming emits d as the duration of phase to handler,
+// if timings are requested by cfg.
+func EmitTiming(handler govulncheck.Handler, cfg *govulncheck.Config, phase string, d time.Duration) error {
+	if !cfg.EmitTimings {
+		return nil
+	}
+	return handler.Timing(&govulncheck.Timing{Phase: phase, Duration: d})
+}
+
 // emitModuleFindings emits module-level findings for vulnerabilities in modVulns.
+//
+// A module can be used at several versions, for instance when another
+// module is replaced by one of its versions. Each version gets its own
+// finding, while a vulnerability is reported only once per module version.
 func emitModuleFindings(handler govulncheck.Handler, affVulns affectingVulns) error {
+	type key struct{ osv, path, version string }
+	seen := make(map[key]bool)
+	var findings []*govulncheck.Finding
 	for _, vuln := range affVulns {
 		for _, osv := range vuln.Vulns {
-			if err := handler.Finding(&govulncheck.Finding{
-				OSV:          osv.ID,
-				FixedVersion: FixedVersion(modPath(vuln.Module), modVersion(vuln.Module), osv.Affected),
-				Trace:        []*govulncheck.Frame{frameFromModule(vuln.Module)},
-			}); err != nil {
-				return err
+			k := key{osv.ID, modPath(vuln.Module), vuln.version(osv.ID)}
+			if seen[k] {
+				continue
 			}
+			seen[k] = true
+			findings = append(findings, &govulncheck.Finding{
+				OSV:               osv.ID,
+				Level:             govulncheck.ScanLevelModule,
+				FixedVersion:      FixedVersion(modPath(vuln.Module), vuln.version(osv.ID), osv.Affected),
+				FixedVersions:     FixedVersions(modPath(vuln.Module), vuln.version(osv.ID), osv.Affected),
+				IntroducedVersion: IntroducedVersion(modPath(vuln.Module), vuln.version(osv.ID), osv.Affected),
+				MatchedVersion:    vuln.Versions[osv.ID],
+				Trace:             []*govulncheck.Frame{frameFromModule(vuln.Module)},
+			})
 		}
 	}
-	return nil
+	return emitFindings(handler, findings)
 }
 
 // emitPackageFinding emits package-level findings fod vulnerabilities in vulns.
-func emitPackageFindings(handler govulncheck.Handler, vulns []*Vuln) error {
+func emitPackageFindings(handler govulncheck.Handler, vulns []*Vuln, graph *PackageGraph) error {
+	var findings []*govulncheck.Finding
 	for _, v := range vulns {
-		if err := handler.Finding(&govulncheck.Finding{
-			OSV:          v.OSV.ID,
-			FixedVersion: FixedVersion(modPath(v.Package.Module), modVersion(v.Package.Module), v.OSV.Affected),
-			Trace:        []*govulncheck.Frame{frameFromPackage(v.Package)},
-		}); err != nil {
-			return err
+		f := &govulncheck.Finding{
+			OSV:               v.OSV.ID,
+			Level:             govulncheck.ScanLevelPackage,
+			FixedVersion:      FixedVersion(modPath(v.Package.Module), vulnVersion(v), v.OSV.Affected),
+			FixedVersions:     FixedVersions(modPath(v.Package.Module), vulnVersion(v), v.OSV.Affected),
+			IntroducedVersion: IntroducedVersion(modPath(v.Package.Module), vulnVersion(v), v.OSV.Affected),
+			MatchedVersion:    v.Version,
+			Trace:             []*govulncheck.Frame{frameFromPackage(v.Package)},
 		}
+		if graph != nil {
+			f.ImportChain = graph.importChain(v.Package)
+		}
+		findings = append(findings, f)
 	}
-	return nil
+	return emitFindings(handler, findings)
 }
 
 // emitCallFindings emits call-level findings for vulnerabilities
-// that have a call stack in callstacks.
-func emitCallFindings(handler govulncheck.Handler, callstacks map[*Vuln]CallStack) error {
-	var vulns []*Vuln
-	for v := range callstacks {
-		vulns = append(vulns, v)
-	}
-
-	for _, vuln := range vulns {
-		stack := callstacks[vuln]
+// that have a call stack in callstacks. The findings count the
+// entry functions in entryPoints, and list them if listEntries is set.
+func emitCallFindings(handler govulncheck.Handler, callstacks map[*Vuln]CallStack, entryPoints map[*Vuln][]*FuncNode, listEntries bool, reach govulncheck.Reachability) error {
+	var findings []*govulncheck.Finding
+	for vuln, stack := range callstacks {
 		if stack == nil {
 			continue
 		}
-		fixed := FixedVersion(modPath(vuln.Package.Module), modVersion(vuln.Package.Module), vuln.OSV.Affected)
-		if err := handler.Finding(&govulncheck.Finding{
-			OSV:          vuln.OSV.ID,
-			FixedVersion: fixed,
-			Trace:        traceFromEntries(stack),
-		}); err != nil {
+		findings = append(findings, callFinding(vuln, stack, entryPoints[vuln], listEntries, reach))
+	}
+	return emitFindings(handler, findings)
+}
+
+// emitAlternativeCallFindings emits a call-level finding for each
+// of the alternative call stacks of vulnerabilities in callstacks.
+// They are emitted after the findings of emitCallFindings, with the
+// same entry points and reachability.
+func emitAlternativeCallFindings(handler govulncheck.Handler, callstacks map[*Vuln][]CallStack, entryPoints map[*Vuln][]*FuncNode, listEntries bool, reach govulncheck.Reachability) error {
+	var findings []*govulncheck.Finding
+	for vuln, stacks := range callstacks {
+		for _, stack := range stacks {
+			findings = append(findings, callFinding(vuln, stack, entryPoints[vuln], listEntries, reach))
+		}
+	}
+	return emitFindings(handler, findings)
+}
+
+// vulnVersion returns the version of the module of vuln.Package
+// that vuln.OSV affects.
+func vulnVersion(vuln *Vuln) string {
+	if vuln.Version != "" {
+		return vuln.Version
+	}
+	return modVersion(vuln.Package.Module)
+}
+
+// callFinding returns the call-level finding of vuln with stack.
+func callFinding(vuln *Vuln, stack CallStack, entryPoints []*FuncNode, listEntries bool, reach govulncheck.Reachability) *govulncheck.Finding {
+	f := &govulncheck.Finding{
+		OSV:               vuln.OSV.ID,
+		Level:             govulncheck.ScanLevelSymbol,
+		FixedVersion:      FixedVersion(modPath(vuln.Package.Module), vulnVersion(vuln), vuln.OSV.Affected),
+		FixedVersions:     FixedVersions(modPath(vuln.Package.Module), vulnVersion(vuln), vuln.OSV.Affected),
+		IntroducedVersion: IntroducedVersion(modPath(vuln.Package.Module), vulnVersion(vuln), vuln.OSV.Affected),
+		MatchedVersion:    vuln.Version,
+		Trace:             traceFromEntries(stack),
+		EntryPointCount:   len(entryPoints),
+		Reachability:      reach,
+	}
+	if listEntries {
+		f.EntryPoints = framesFromFuncs(entryPoints)
+	}
+	return f
+}
+
+// framesFromFuncs returns a frame for each function in fns.
+// The position of a frame is the position of its function.
+func framesFromFuncs(fns []*FuncNode) []*govulncheck.Frame {
+	var frames []*govulncheck.Frame
+	for _, fn := range fns {
+		fr := frameFromPackage(fn.Package)
+		fr.Function = fn.Name
+		fr.Receiver = fn.Receiver()
+		fr.Generated = fn.Generated
+		if p := fn.Pos; p != nil {
+			fr.Position = &govulncheck.Position{
+				Filename: pathRelativeToMod(p.Filename, fn),
+				Offset:   p.Offset,
+				Line:     p.Line,
+				Column:   p.Column,
+			}
+		}
+		frames = append(frames, fr)
+	}
+	return frames
+}
+
+// emitFindings emits findings to handler in a deterministic order:
+// by OSV ID, then by the module, module version, package, and
+// symbol of the vulnerable frame.
+func emitFindings(handler govulncheck.Handler, findings []*govulncheck.Finding) error {
+	slices.SortStableFunc(findings, func(a, b *govulncheck.Finding) int {
+		if c := strings.Compare(a.OSV, b.OSV); c != 0 {
+			return c
+		}
+		fa, fb := a.Trace[0], b.Trace[0]
+		if c := strings.Compare(fa.Module, fb.Module); c != 0 {
+			return c
+		}
+		if c := strings.Compare(fa.Version, fb.Version); c != 0 {
+			return c
+		}
+		if c := strings.Compare(fa.Package, fb.Package); c != 0 {
+			return c
+		}
+		if c := strings.Compare(fa.Receiver, fb.Receiver); c != 0 {
+			return c
+		}
+		return strings.Compare(fa.Function, fb.Function)
+	})
+	for _, f := range findings {
+		if err := handler.Finding(f); err != nil {
 			return err
 		}
 	}
@@ -91,7 +287,13 @@ func traceFromEntries(vcs CallStack) []*govulncheck.Frame {
 		fr := frameFromPackage(e.Function.Package)
 		fr.Function = e.Function.Name
 		fr.Receiver = e.Function.Receiver()
+		fr.Generated = e.Function.Generated
 		isSink := i == (len(vcs) - 1)
+		if isSink {
+			// Report the vulnerable symbol as it appears in the
+			// database, without type arguments of an instantiation.
+			fr.Function, _, _ = strings.Cut(fr.Function, "[")
+		}
 		fr.Position = posFromStackEntry(e, isSink)
 		frames = append(frames, fr)
 	}
@@ -196,3 +398,14 @@ func frameFromModule(mod *packages.Module) *govulncheck.Frame {
 
 	return fr
 }
+
+// emitSymbolsFailed reports that the symbol level analysis of a scan
+// failed with err, with a warning marked as such, so that handlers
+// report the results as those of a package level scan. Otherwise,
+// a clean symbol scan would hide the vulnerable packages imported.
+func emitSymbolsFailed(handler govulncheck.Handler, err *SymbolsError) error {
+	return handler.Progress(&govulncheck.Progress{
+		Message:       "warning: " + err.Error(),
+		SymbolsFailed: true,
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vulncheck
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestEmitPackageFindingsOrder(t *testing.T) {
+	mod := &packages.Module{Path: "example.mod/a", Version: "v1.0.0"}
+	pkg := func(path string) *packages.Package {
+		return &packages.Package{PkgPath: path, Module: mod}
+	}
+	vulns := []*Vuln{
+		{OSV: &osv.Entry{ID: "GO-0000-0002"}, Package: pkg("example.mod/a/b")},
+		{OSV: &osv.Entry{ID: "GO-0000-0001"}, Package: pkg("example.mod/a/c")},
+		{OSV: &osv.Entry{ID: "GO-0000-0002"}, Package: pkg("example.mod/a/a")},
+		{OSV: &osv.Entry{ID: "GO-0000-0001"}, Package: pkg("example.mod/a/b")},
+	}
+
+	handler := test.NewMockHandler()
+	if err := emitPackageFindings(handler, vulns, nil); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range handler.FindingMessages {
+		got = append(got, f.OSV+" "+f.Trace[0].Package)
+	}
+	want := []string{
+		"GO-0000-0001 example.mod/a/b",
+		"GO-0000-0001 example.mod/a/c",
+		"GO-0000-0002 example.mod/a/a",
+		"GO-0000-0002 example.mod/a/b",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestEmitLocalReplaceWarnings(t *testing.T) {
+	mvs := []*ModVulns{
+		{
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.0.0"},
+			Vulns:  []*osv.Entry{{ID: "GO-0000-0001"}},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/b", Version: "v1.0.0", Replace: &packages.Module{Path: "example.mod/c", Version: "v1.1.0"}},
+			Vulns:  []*osv.Entry{{ID: "GO-0000-0002"}},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/d", Version: "v1.0.0", Replace: &packages.Module{Path: "../d"}},
+			Vulns:  []*osv.Entry{{ID: "GO-0000-0003"}, {ID: "GO-0000-0004"}},
+		},
+	}
+
+	handler := test.NewMockHandler()
+	if err := emitLocalReplaceWarnings(handler, mvs); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range handler.ProgressMessages {
+		got = append(got, p.Message)
+	}
+	want := []string{"warning: skipping 2 known vulnerabilities of example.mod/d, which is replaced by the unversioned local directory ../d"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestEmitRangeWarnings(t *testing.T) {
+	git := []osv.Range{{Type: osv.RangeTypeGit, Events: []osv.RangeEvent{
+		{Introduced: "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"},
+		{Fixed: "0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e"},
+	}}}
+	mvs := []*ModVulns{
+		{
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.0.0"},
+			Vulns:  []*osv.Entry{{ID: "GO-0000-0001", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/a"}, Ranges: git}}}},
+		},
+		{
+			// pseudo-version of the fixing commit
+			Module: &packages.Module{Path: "example.mod/b", Version: "v0.0.0-20230201000000-0f9e8d7c6b5a"},
+			Vulns:  []*osv.Entry{{ID: "GO-0000-0002", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/b"}, Ranges: git}}}},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/c", Version: "v1.0.0"},
+			Vulns: []*osv.Entry{{ID: "GO-0000-0003", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/c"}, Ranges: []osv.Range{
+				{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}}},
+			}}}}},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/d", Version: "v1.0.0"},
+			Vulns: []*osv.Entry{{ID: "GO-0000-0004", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/d"}, Ranges: []osv.Range{
+				{Type: osv.RangeTypeEcosystem, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "2024-01-01"}}},
+			}}}}},
+		},
+		{
+			// ECOSYSTEM ranges of module versions are evaluated
+			Module: &packages.Module{Path: "example.mod/e", Version: "v1.0.0"},
+			Vulns: []*osv.Entry{{ID: "GO-0000-0005", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/e"}, Ranges: []osv.Range{
+				{Type: osv.RangeTypeEcosystem, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.2.0"}}},
+			}}}}},
+		},
+	}
+
+	handler := test.NewMockHandler()
+	if err := emitRangeWarnings(handler, mvs); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range handler.ProgressMessages {
+		got = append(got, p.Message)
+	}
+	want := []string{
+		"warning: could not evaluate the git commit ranges of GO-0000-0001 for example.mod/a@v1.0.0, assuming it is affected",
+		"warning: could not evaluate the ECOSYSTEM ranges of GO-0000-0004 for example.mod/d@v1.0.0, assuming it is affected",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestEmitSymbolsFailed(t *testing.T) {
+	handler := test.NewMockHandler()
+	if err := emitSymbolsFailed(handler, &SymbolsError{Err: errors.New("boom")}); err != nil {
+		t.Fatal(err)
+	}
+	if len(handler.ProgressMessages) != 1 {
+		t.Fatalf("got progress messages %v, want one warning", handler.ProgressMessages)
+	}
+	if p := handler.ProgressMessages[0]; !p.SymbolsFailed || !strings.Contains(p.Message, "boom") {
+		t.Errorf("got progress %+v, want a warning with the error and SymbolsFailed set", p)
+	}
+	// The Config of the stream is emitted once, before anything else.
+	if len(handler.ConfigMessages) != 0 {
+		t.Errorf("got config messages %v, want none", handler.ConfigMessages)
+	}
+}
+
+func TestEmitOSVs(t *testing.T) {
+	shared := &osv.Entry{ID: "GO-0000-0002"}
+	mvs := []*ModVulns{
+		{
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.0.0"},
+			Vulns:  []*osv.Entry{shared, {ID: "GO-0000-0003"}},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/b", Version: "v1.0.0"},
+			Vulns:  []*osv.Entry{{ID: "GO-0000-0001"}, shared},
+		},
+	}
+
+	handler := test.NewMockHandler()
+	if err := emitOSVs(handler, mvs); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range handler.OSVMessages {
+		got = append(got, e.ID)
+	}
+	want := []string{"GO-0000-0001", "GO-0000-0002", "GO-0000-0003"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestEmitModuleFindingsVersions(t *testing.T) {
+	entry := &osv.Entry{
+		ID: "GO-0000-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "example.mod/v"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.2.0"}}}},
+		}},
+	}
+	vmod := &packages.Module{Path: "example.mod/v", Version: "v1.0.0"}
+	affVulns := affectingVulns{
+		{Module: vmod, Vulns: []*osv.Entry{entry}},
+		// example.mod/r is replaced by the same version of example.mod/v.
+		{Module: &packages.Module{Path: "example.mod/r", Version: "v1.0.0", Replace: vmod}, Vulns: []*osv.Entry{entry}},
+		// example.mod/s is replaced by another version of example.mod/v.
+		{Module: &packages.Module{Path: "example.mod/s", Version: "v1.0.0",
+			Replace: &packages.Module{Path: "example.mod/v", Version: "v1.1.0"}}, Vulns: []*osv.Entry{entry}},
+	}
+
+	handler := test.NewMockHandler()
+	if err := emitModuleFindings(handler, affVulns); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range handler.FindingMessages {
+		got = append(got, f.Trace[0].Module+"@"+f.Trace[0].Version)
+	}
+	want := []string{"example.mod/v@v1.0.0", "example.mod/v@v1.1.0"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestPathRelativeToMod(t *testing.T) {
+	root := filepath.FromSlash("/home/user/src/m")
+	mod := &packages.Module{Path: "example.com/m", Dir: root}
+	replaced := &packages.Module{Path: "example.com/r", Replace: mod}
+	vendored := &packages.Module{Path: "example.com/v"}
+	fn := func(m *packages.Module) *FuncNode {
+		return &FuncNode{Package: &packages.Package{Module: m}}
+	}
+	for _, test := range []struct {
+		path string
+		f    *FuncNode
+		want string
+	}{
+		{filepath.Join(root, "x.go"), fn(mod), "x.go"},
+		{filepath.Join(root, "a", "b", "x.go"), fn(mod), "a/b/x.go"},
+		{filepath.Join(root, "a", "x.go"), fn(replaced), "a/x.go"},
+		{filepath.Join(root, "vendor", "example.com", "v", "p", "x.go"), fn(vendored), "p/x.go"},
+		{"", fn(mod), ""},
+		{filepath.Join(root, "x.go"), nil, ""},
+	} {
+		if got := pathRelativeToMod(test.path, test.f); got != test.want {
+			t.Errorf("pathRelativeToMod(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package vulncheck
 import (
 	"strings"
 
+	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 )
 
@@ -45,6 +46,19 @@ func entryPoints(topPackages []*ssa.Package) []*ssa.Function {
 	return entries
 }
 
+// hasEntryFunctions reports whether entries has functions other than
+// package initializers. Main packages without a main function and
+// packages without exported functions, for instance because build
+// tags exclude the files declaring them, have no such functions.
+func hasEntryFunctions(entries []*ssa.Function) bool {
+	for _, f := range entries {
+		if f.Synthetic != "package initializer" {
+			return true
+		}
+	}
+	return false
+}
+
 func isEntry(f *ssa.Function) bool {
 	// it should be safe to ignore checking that the signature of the "init" function
 	// is valid, since it is synthetic
@@ -54,3 +68,28 @@ func isEntry(f *ssa.Function) bool {
 
 	return f.Synthetic == "" && f.Object() != nil && f.Object().Exported()
 }
+
+// libraryPackages returns the packages of pkgs that other modules
+// can import from the main modules, see -as-library. Their exported
+// functions and methods are then the entry points of the analysis.
+func libraryPackages(pkgs []*packages.Package) []*packages.Package {
+	var lib []*packages.Package
+	for _, p := range pkgs {
+		if p.Module == nil || !p.Module.Main || p.Name == "main" || isInternal(p.PkgPath) {
+			continue
+		}
+		lib = append(lib, p)
+	}
+	return lib
+}
+
+// isInternal reports whether the package at path is an internal
+// package, which only packages of the same tree can import.
+func isInternal(path string) bool {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "internal" {
+			return true
+		}
+	}
+	return false
+}
@@ -7,29 +7,30 @@ package vulncheck
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/govulncheck"
 )
 
 // FetchVulnerabilities fetches vulnerabilities that affect the supplied modules.
-func FetchVulnerabilities(ctx context.Context, c *client.Client, modules []*packages.Module) ([]*ModVulns, error) {
+// It also returns the number of database entries of the modules that were
+// skipped for not being in the Go ecosystem.
+func FetchVulnerabilities(ctx context.Context, c *client.Client, modules []*packages.Module) (_ []*ModVulns, skipped int, _ error) {
 	mreqs := make([]*client.ModuleRequest, len(modules))
 	for i, mod := range modules {
-		modPath := mod.Path
-		if mod.Replace != nil {
-			modPath = mod.Replace.Path
-		}
 		mreqs[i] = &client.ModuleRequest{
-			Path: modPath,
+			Path: lookupPath(mod),
 		}
 	}
 	resps, err := c.ByModules(ctx, mreqs)
 	if err != nil {
-		return nil, fmt.Errorf("fetching vulnerabilities: %v", err)
+		return nil, 0, fmt.Errorf("fetching vulnerabilities: %v", err)
 	}
 	var mv []*ModVulns
 	for i, resp := range resps {
+		skipped += resp.SkippedNonGo
 		if len(resp.Entries) == 0 {
 			continue
 		}
@@ -38,5 +39,31 @@ func FetchVulnerabilities(ctx context.Context, c *client.Client, modules []*pack
 			Vulns:  resp.Entries,
 		})
 	}
-	return mv, nil
+	return mv, skipped, nil
+}
+
+// emitSkippedNonGo reports, as progress, how many database
+// entries were skipped for not being in the Go ecosystem, if any.
+func emitSkippedNonGo(handler govulncheck.Handler, n int) error {
+	if n == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("Skipped %d vulnerability database entries not in the Go ecosystem.", n)
+	return handler.Progress(&govulncheck.Progress{Message: msg})
+}
+
+// skipModules returns the modules whose path, or replacement
+// path, is not in skip.
+func skipModules(modules []*packages.Module, skip []string) []*packages.Module {
+	if len(skip) == 0 {
+		return modules
+	}
+	var mods []*packages.Module
+	for _, m := range modules {
+		if slices.Contains(skip, m.Path) || (m.Replace != nil && slices.Contains(skip, m.Replace.Path)) {
+			continue
+		}
+		mods = append(mods, m)
+	}
+	return mods
 }
@@ -20,17 +20,19 @@ func TestFetchVulnerabilities(t *testing.T) {
 	b := &osv.Entry{ID: "b", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/b"}, Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Fixed: "1.1.1"}}}}}}}
 	c := &osv.Entry{ID: "c", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/d"}, Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Fixed: "2.0.0"}}}}}}}
 	d := &osv.Entry{ID: "e", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/e"}, Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Fixed: "2.2.0"}}}}}}}
+	f := &osv.Entry{ID: "f", Affected: []osv.Affected{{Module: osv.Module{Path: "example.mod/f"}, Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Fixed: "1.2.0"}}}}}}}
 
-	mc, err := client.NewInMemoryClient([]*osv.Entry{a, b, c, d})
+	mc, err := client.NewInMemoryClient([]*osv.Entry{a, b, c, d, f})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	got, err := vulncheck.FetchVulnerabilities(context.Background(), mc, []*packages.Module{
+	got, _, err := vulncheck.FetchVulnerabilities(context.Background(), mc, []*packages.Module{
 		{Path: "example.mod/a", Version: "v1.0.0"},
 		{Path: "example.mod/b", Version: "v1.0.4"},
 		{Path: "example.mod/c", Replace: &packages.Module{Path: "example.mod/d", Version: "v1.0.0"}, Version: "v2.0.0"},
 		{Path: "example.mod/e", Replace: &packages.Module{Path: "../local/example.mod/d", Version: "v1.0.1"}, Version: "v2.1.0"},
+		{Path: "example.mod/f", Replace: &packages.Module{Path: "../local/example.mod/f"}, Version: "v1.1.0"},
 	})
 	if err != nil {
 		t.Fatalf("FetchVulnerabilities failed: %s", err)
@@ -49,6 +51,11 @@ func TestFetchVulnerabilities(t *testing.T) {
 			Module: &packages.Module{Path: "example.mod/c", Replace: &packages.Module{Path: "example.mod/d", Version: "v1.0.0"}, Version: "v2.0.0"},
 			Vulns:  []*osv.Entry{c},
 		},
+		{
+			// Unversioned local replacements are looked up under the original path.
+			Module: &packages.Module{Path: "example.mod/f", Replace: &packages.Module{Path: "../local/example.mod/f"}, Version: "v1.1.0"},
+			Vulns:  []*osv.Entry{f},
+		},
 	}
 	if diff := cmp.Diff(got, want); diff != "" {
 		t.Fatalf("mismatch (-want, +got):\n%s", diff)
@@ -5,12 +5,18 @@
 package vulncheck
 
 import (
+	"context"
+	"path"
 	"runtime"
 	"sort"
+	"testing"
 
+	"golang.org/x/tools/go/packages/packagestest"
 	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
 	"golang.org/x/vuln/internal/semver"
+	"golang.org/x/vuln/internal/test"
 )
 
 // newTestClient returns a client that reads
@@ -63,6 +69,48 @@ func newTestClient() (*client.Client, error) {
 			}})
 }
 
+// loadTestGraph exports modules and loads the packages matching
+// pattern, a path relative to the export directory, with tags.
+// The packages are loaded for call analysis if wantSymbols is set.
+func loadTestGraph(t *testing.T, modules []packagestest.Module, tags []string, pattern string, wantSymbols bool) *PackageGraph {
+	t.Helper()
+	e := packagestest.Export(t, packagestest.Modules, modules)
+	t.Cleanup(e.Cleanup)
+	graph := NewPackageGraph("go1.18")
+	if err := graph.LoadPackagesAndMods(e.Config, tags, []string{path.Join(e.Temp(), pattern)}, wantSymbols); err != nil {
+		t.Fatal(err)
+	}
+	return graph
+}
+
+// runTestSource runs Source on graph with cfg against the database
+// of c and returns the handler gathering the messages it emits.
+func runTestSource(t *testing.T, cfg *govulncheck.Config, c *client.Client, graph *PackageGraph) *test.MockHandler {
+	t.Helper()
+	handler := test.NewMockHandler()
+	if err := Source(context.Background(), handler, cfg, c, graph); err != nil {
+		t.Fatal(err)
+	}
+	return handler
+}
+
+// symbolTraces returns the traces of the symbol level findings
+// gathered by handler, with each frame as pkg.symbol.
+func symbolTraces(handler *test.MockHandler) [][]string {
+	var traces [][]string
+	for _, f := range handler.FindingMessages {
+		if len(f.Trace) < 2 {
+			continue // not a symbol-level finding
+		}
+		var trace []string
+		for _, fr := range f.Trace {
+			trace = append(trace, fr.Package+"."+symbol(fr))
+		}
+		traces = append(traces, trace)
+	}
+	return traces
+}
+
 type edge struct {
 	// src and dest are ids of source and
 	// destination nodes in a callgraph edge.
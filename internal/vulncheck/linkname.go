@@ -0,0 +1,95 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vulncheck
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// linkTarget is the function that a function declared without
+// a body is bound to by a //go:linkname directive.
+type linkTarget struct {
+	pkg  string // import path of the package of the target
+	recv string // receiver type name of a method target, such as "*T"
+	name string // function or method name
+}
+
+// symbol returns the name of t as used in the vulnerability database.
+func (t linkTarget) symbol() string {
+	if t.recv == "" {
+		return t.name
+	}
+	return strings.TrimPrefix(t.recv, "*") + "." + t.name
+}
+
+// recvType returns the full receiver type of a method target,
+// such as "*net/http.Client", in the form of FuncNode.RecvType.
+func (t linkTarget) recvType() string {
+	if t.recv == "" {
+		return ""
+	}
+	if r, ok := strings.CutPrefix(t.recv, "*"); ok {
+		return "*" + t.pkg + "." + r
+	}
+	return t.pkg + "." + t.recv
+}
+
+// linknames maps functions, by the package path and name of
+// their declaration, to the targets of their go:linkname directives.
+type linknames map[string]linkTarget
+
+// graphLinknames collects the go:linkname directives in the
+// syntax of the packages of graph.
+//
+// Calls to a function declared without a body and linked to a
+// function in another package are calls of that function, which
+// the call graph does not capture.
+func graphLinknames(graph *PackageGraph) linknames {
+	links := make(linknames)
+	for _, pkg := range graph.packages {
+		for _, file := range pkg.Syntax {
+			for _, cg := range file.Comments {
+				for _, c := range cg.List {
+					fields := strings.Fields(c.Text)
+					if len(fields) != 3 || fields[0] != "//go:linkname" {
+						continue
+					}
+					if t, ok := parseLinkTarget(fields[2]); ok {
+						links[pkg.PkgPath+"."+fields[1]] = t
+					}
+				}
+			}
+		}
+	}
+	return links
+}
+
+// parseLinkTarget parses the target of a go:linkname directive,
+// such as "net/http.Get" or "net/http.(*Client).Do".
+func parseLinkTarget(s string) (linkTarget, bool) {
+	slash := strings.LastIndex(s, "/") + 1
+	pkg, sym, ok := strings.Cut(s[slash:], ".")
+	if !ok || pkg == "" || sym == "" {
+		return linkTarget{}, false
+	}
+	t := linkTarget{pkg: s[:slash] + pkg, name: sym}
+	if recv, name, ok := strings.Cut(sym, "."); ok {
+		recv = strings.TrimSuffix(strings.TrimPrefix(recv, "("), ")")
+		t.recv, t.name = recv, name
+	}
+	return t, true
+}
+
+// target returns the target f is linked to, if f is a function
+// declared without a body and linked to a function by links.
+func (links linknames) target(f *ssa.Function) (linkTarget, bool) {
+	if len(f.Blocks) > 0 || f.Signature.Recv() != nil || f.Pkg == nil {
+		return linkTarget{}, false
+	}
+	t, ok := links[pkgPath(f)+"."+f.Name()]
+	return t, ok
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vulncheck
+
+import "testing"
+
+func TestParseLinkTarget(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		want   linkTarget
+		symbol string
+		ok     bool
+	}{
+		{"net/http.Get", linkTarget{pkg: "net/http", name: "Get"}, "Get", true},
+		{"runtime.nanotime", linkTarget{pkg: "runtime", name: "nanotime"}, "nanotime", true},
+		{"net/http.(*Client).Do", linkTarget{pkg: "net/http", recv: "*Client", name: "Do"}, "Client.Do", true},
+		{"golang.org/x/text/language.Tag.String", linkTarget{pkg: "golang.org/x/text/language", recv: "Tag", name: "String"}, "Tag.String", true},
+		{"nanotime", linkTarget{}, "", false},
+		{"example.com/m", linkTarget{}, "", false},
+	} {
+		got, ok := parseLinkTarget(test.in)
+		if ok != test.ok || got != test.want {
+			t.Errorf("parseLinkTarget(%q) = %+v, %t; want %+v, %t", test.in, got, ok, test.want, test.ok)
+			continue
+		}
+		if ok && got.symbol() != test.symbol {
+			t.Errorf("parseLinkTarget(%q).symbol() = %q, want %q", test.in, got.symbol(), test.symbol)
+		}
+	}
+}
@@ -5,8 +5,14 @@
 package vulncheck
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"go/ast"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -25,12 +31,18 @@ type PackageGraph struct {
 	topPkgs  []*packages.Package
 	modules  map[string]*packages.Module  // all modules (even replacing ones)
 	packages map[string]*packages.Package // all packages (even dependencies)
+
+	// generated are the files of top-level packages marked as
+	// generated, see ast.IsGenerated. They are only known when
+	// the syntax of packages is loaded, at symbol scan level.
+	generated map[string]bool
 }
 
 func NewPackageGraph(goVersion string) *PackageGraph {
 	graph := &PackageGraph{
-		modules:  map[string]*packages.Module{},
-		packages: map[string]*packages.Package{},
+		modules:   map[string]*packages.Module{},
+		packages:  map[string]*packages.Package{},
+		generated: map[string]bool{},
 	}
 
 	goRoot := ""
@@ -50,6 +62,12 @@ func (g *PackageGraph) TopPkgs() []*packages.Package {
 	return g.topPkgs
 }
 
+// isGenerated reports whether file belongs to a top-level
+// package and is marked as generated.
+func (g *PackageGraph) isGenerated(file string) bool {
+	return g.generated[file]
+}
+
 // DepPkgs returns the number of packages that graph.TopPkgs()
 // strictly depend on. This does not include topPkgs even if
 // they are dependency of each other.
@@ -196,7 +214,10 @@ func (g *PackageGraph) GetPackage(path string) *packages.Package {
 
 // LoadPackages loads the packages specified by the patterns into the graph.
 // See golang.org/x/tools/go/packages.Load for details of how it works.
+// The provided cfg is not modified, so it can be shared by concurrent loads.
 func (g *PackageGraph) LoadPackagesAndMods(cfg *packages.Config, tags []string, patterns []string, wantSymbols bool) error {
+	c := *cfg
+	cfg = &c
 	if len(tags) > 0 {
 		cfg.BuildFlags = []string{fmt.Sprintf("-tags=%s", strings.Join(tags, ","))}
 	}
@@ -219,13 +240,114 @@ func (g *PackageGraph) LoadPackagesAndMods(cfg *packages.Config, tags []string,
 	// This will also add their respective modules.
 	g.AddPackages(pkgs...)
 
-	// save top-level packages
+	// save top-level packages and their generated files
 	for _, p := range pkgs {
 		g.topPkgs = append(g.topPkgs, g.GetPackage(p.PkgPath))
+		for _, f := range p.Syntax {
+			if ast.IsGenerated(f) {
+				g.generated[p.Fset.Position(f.FileStart).Filename] = true
+			}
+		}
 	}
+	g.addVendoredVersions()
 	return err
 }
 
+// ResolveUnknownModules assigns a module to each package of the
+// unknown module, such as packages loaded in GOPATH mode, using
+// resolve. Packages for which resolve returns nil are left as is.
+func (g *PackageGraph) ResolveUnknownModules(resolve func(*packages.Package) *packages.Module) {
+	unknown := g.GetModule(internal.UnknownModulePath)
+	for _, p := range g.packages {
+		if p.Module != unknown {
+			continue
+		}
+		if m := resolve(p); m != nil {
+			g.AddModules(m)
+			p.Module = g.GetModule(m.Path)
+		}
+	}
+}
+
+// addVendoredVersions sets the versions and replacements of modules
+// that were loaded without them using the information recorded in
+// the vendor/modules.txt file of the main modules, if any.
+//
+// Depending on the version of the go command, packages loaded
+// from a vendor directory might not have module versions
+// populated, which would make module-level vulnerability
+// checks silently miss findings.
+func (g *PackageGraph) addVendoredVersions() {
+	vendored := make(map[string]*packages.Module)
+	for _, p := range g.topPkgs {
+		m := p.Module
+		if m == nil || !m.Main || m.Dir == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(m.Dir, "vendor", "modules.txt"))
+		if err != nil {
+			continue // no vendoring
+		}
+		for path, vm := range parseVendoredModules(data) {
+			vendored[path] = vm
+		}
+	}
+	if len(vendored) == 0 {
+		return
+	}
+	for path, m := range g.modules {
+		vm, ok := vendored[path]
+		if m.Main || !ok {
+			continue
+		}
+		if m.Version == "" {
+			m.Version = vm.Version
+		}
+		if m.Replace == nil {
+			m.Replace = vm.Replace
+		} else if m.Replace.Version == "" && vm.Replace != nil && m.Replace.Path == vm.Replace.Path {
+			m.Replace.Version = vm.Replace.Version
+		}
+	}
+}
+
+// parseVendoredModules parses the content of a vendor/modules.txt
+// file and returns a map from module paths to the modules, with
+// their versions and replacements. A replacement module with a
+// different path is also recorded under its own path.
+func parseVendoredModules(data []byte) map[string]*packages.Module {
+	modules := make(map[string]*packages.Module)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Module lines have the form
+		//   # path [version] [=> replacement [version]]
+		// while lines starting with "##" are annotations.
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "# "))
+		if len(fields) == 0 {
+			continue
+		}
+		m := &packages.Module{Path: fields[0]}
+		if len(fields) >= 2 && fields[1] != "=>" {
+			m.Version = fields[1]
+		}
+		if i := slices.Index(fields, "=>"); i >= 0 && len(fields) > i+1 {
+			m.Replace = &packages.Module{Path: fields[i+1]}
+			if len(fields) == i+3 {
+				m.Replace.Version = fields[i+2]
+			}
+			if m.Replace.Path != m.Path && m.Replace.Version != "" {
+				modules[m.Replace.Path] = &packages.Module{Path: m.Replace.Path, Version: m.Replace.Version}
+			}
+		}
+		modules[m.Path] = m
+	}
+	return modules
+}
+
 func addLoadMode(cfg *packages.Config, wantSymbols bool) {
 	cfg.Mode |=
 		packages.NeedModule |
@@ -237,11 +359,161 @@ func addLoadMode(cfg *packages.Config, wantSymbols bool) {
 	}
 }
 
+// importSlice returns the slice of g leading from the top-level
+// packages to the packages of vulns, along with the corresponding
+// slice of the module graph.
+func (g *PackageGraph) importSlice(vulns []*Vuln) *govulncheck.Graph {
+	targets := make(map[*packages.Package]bool)
+	for _, v := range vulns {
+		targets[v.Package] = true
+	}
+
+	// reaches memoizes whether a package reaches a target.
+	reaches := make(map[*packages.Package]bool)
+	var visit func(*packages.Package) bool
+	visit = func(p *packages.Package) bool {
+		if r, ok := reaches[p]; ok {
+			return r
+		}
+		reaches[p] = false // cut cycles
+		r := targets[p]
+		for _, imp := range p.Imports {
+			if visit(imp) {
+				r = true
+			}
+		}
+		reaches[p] = r
+		return r
+	}
+	for _, p := range g.topPkgs {
+		visit(p)
+	}
+
+	pkgNodes := make(map[string]*govulncheck.GraphNode)
+	modNodes := make(map[string]*govulncheck.GraphNode)
+	modNode := func(m *packages.Module) *govulncheck.GraphNode {
+		if n, ok := modNodes[m.Path]; ok {
+			return n
+		}
+		n := &govulncheck.GraphNode{Path: m.Path, Version: modVersion(m)}
+		modNodes[m.Path] = n
+		return n
+	}
+	for p, r := range reaches {
+		if !r {
+			continue
+		}
+		pn := &govulncheck.GraphNode{Path: p.PkgPath}
+		pkgNodes[p.PkgPath] = pn
+		mn := modNode(p.Module)
+		for _, imp := range p.Imports {
+			if !reaches[imp] {
+				continue
+			}
+			pn.Edges = append(pn.Edges, imp.PkgPath)
+			if imp.Module.Path != p.Module.Path && !slices.Contains(mn.Edges, imp.Module.Path) {
+				mn.Edges = append(mn.Edges, imp.Module.Path)
+			}
+		}
+	}
+
+	// Sort for deterministic output.
+	sorted := func(nodes map[string]*govulncheck.GraphNode) []*govulncheck.GraphNode {
+		var ns []*govulncheck.GraphNode
+		for _, n := range nodes {
+			slices.Sort(n.Edges)
+			ns = append(ns, n)
+		}
+		slices.SortFunc(ns, func(a, b *govulncheck.GraphNode) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+		return ns
+	}
+	return &govulncheck.Graph{
+		Packages: sorted(pkgNodes),
+		Modules:  sorted(modNodes),
+	}
+}
+
+// directPkgs returns the paths of the top-level
+// packages and of the packages they import directly.
+func (g *PackageGraph) directPkgs() map[string]bool {
+	direct := make(map[string]bool)
+	for _, p := range g.topPkgs {
+		direct[p.PkgPath] = true
+		for path := range p.Imports {
+			direct[path] = true
+		}
+	}
+	return direct
+}
+
+// pkgModules returns the modules of the packages with the given paths.
+func (g *PackageGraph) pkgModules(paths map[string]bool) []*packages.Module {
+	var mods []*packages.Module
+	for path := range paths {
+		m := g.GetPackage(path).Module
+		if m != nil && !slices.Contains(mods, m) {
+			mods = append(mods, m)
+		}
+	}
+	return mods
+}
+
+// importChain returns the import paths of the packages on a shortest
+// chain of imports from a top-level package to pkg, starting with pkg.
+// It returns nil if no top-level package imports pkg.
+func (g *PackageGraph) importChain(pkg *packages.Package) []string {
+	// Breadth-first search from the top-level packages,
+	// visiting imports in order of path for determinism.
+	parent := make(map[*packages.Package]*packages.Package)
+	queue := slices.Clone(g.topPkgs)
+	for _, p := range queue {
+		parent[p] = nil
+	}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if p == pkg {
+			var chain []string
+			for ; p != nil; p = parent[p] {
+				chain = append(chain, p.PkgPath)
+			}
+			return chain
+		}
+		var paths []string
+		for path := range p.Imports {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+		for _, path := range paths {
+			imp := p.Imports[path]
+			if _, seen := parent[imp]; !seen {
+				parent[imp] = p
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return nil
+}
+
 // packageError contains errors from loading a set of packages.
 type packageError struct {
 	Errors []packages.Error
 }
 
+// PackageErrors returns the errors of the loaded packages reported by
+// err, as returned by LoadPackagesAndMods, or nil if err is not about
+// errors in the packages. The packages are loaded despite such errors,
+// but may be incomplete.
+func PackageErrors(err error) []packages.Error {
+	var pe *packageError
+	if errors.As(err, &pe) {
+		return pe.Errors
+	}
+	return nil
+}
+
 func (e *packageError) Error() string {
 	var b strings.Builder
 	fmt.Fprintln(&b, "\nThere are errors with the provided package patterns:")
@@ -253,26 +525,25 @@ func (e *packageError) Error() string {
 	return b.String()
 }
 
-func (g *PackageGraph) SBOM() *govulncheck.SBOM {
-	getMod := func(mod *packages.Module) *govulncheck.Module {
-		if mod.Replace != nil {
-			return &govulncheck.Module{
-				Path:    mod.Replace.Path,
-				Version: mod.Replace.Version,
-			}
-		}
-
-		return &govulncheck.Module{
-			Path:    mod.Path,
-			Version: mod.Version,
-		}
+// sbomModule returns the SBOM module of mod, which
+// is its replacement, if any.
+func sbomModule(mod *packages.Module) *govulncheck.Module {
+	if mod.Replace == nil {
+		return &govulncheck.Module{Path: mod.Path, Version: mod.Version}
+	}
+	m := &govulncheck.Module{Path: mod.Replace.Path, Version: mod.Replace.Version}
+	if mod.Replace.Path != mod.Path {
+		m.Replaces = mod.Path
 	}
+	return m
+}
 
+func (g *PackageGraph) SBOM() *govulncheck.SBOM {
 	var roots []string
 	rootMods := make(map[string]*govulncheck.Module)
 	for _, pkg := range g.TopPkgs() {
 		roots = append(roots, pkg.PkgPath)
-		mod := getMod(pkg.Module)
+		mod := sbomModule(pkg.Module)
 		rootMods[mod.Path] = mod
 	}
 
@@ -282,7 +553,7 @@ func (g *PackageGraph) SBOM() *govulncheck.SBOM {
 	var topMods, depMods []*govulncheck.Module
 	var goVersion string
 	for _, mod := range g.Modules() {
-		mod := getMod(mod)
+		mod := sbomModule(mod)
 
 		if mod.Path == internal.GoStdModulePath {
 			goVersion = semver.SemverToGoTag(mod.Version)
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vulncheck
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+func TestParseVendoredModules(t *testing.T) {
+	data := []byte(`# github.com/tidwall/gjson v1.6.5
+## explicit; go 1.12
+github.com/tidwall/gjson
+# golang.org/x/text v0.3.0 => golang.org/x/text v0.3.5
+## explicit
+golang.org/x/text/language
+# example.com/local => ../local
+example.com/local
+# example.com/replaced v1.0.0 => example.com/fork v1.1.0
+# example.com/all => example.com/allfork v2.0.0
+`)
+	want := map[string]*packages.Module{
+		"github.com/tidwall/gjson": {Path: "github.com/tidwall/gjson", Version: "v1.6.5"},
+		"golang.org/x/text": {Path: "golang.org/x/text", Version: "v0.3.0",
+			Replace: &packages.Module{Path: "golang.org/x/text", Version: "v0.3.5"}},
+		"example.com/local": {Path: "example.com/local",
+			Replace: &packages.Module{Path: "../local"}},
+		"example.com/replaced": {Path: "example.com/replaced", Version: "v1.0.0",
+			Replace: &packages.Module{Path: "example.com/fork", Version: "v1.1.0"}},
+		"example.com/fork": {Path: "example.com/fork", Version: "v1.1.0"},
+		"example.com/all": {Path: "example.com/all",
+			Replace: &packages.Module{Path: "example.com/allfork", Version: "v2.0.0"}},
+		"example.com/allfork": {Path: "example.com/allfork", Version: "v2.0.0"},
+	}
+	got := parseVendoredModules(data)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestImportChain(t *testing.T) {
+	pkg := func(path string, imports ...*packages.Package) *packages.Package {
+		p := &packages.Package{PkgPath: path, Imports: make(map[string]*packages.Package)}
+		for _, imp := range imports {
+			p.Imports[imp.PkgPath] = imp
+		}
+		return p
+	}
+	v := pkg("example.com/v")
+	c := pkg("example.com/c", v)
+	b := pkg("example.com/b", c)
+	a := pkg("example.com/a", v)
+	root := pkg("example.com/root", b, a)
+	other := pkg("example.com/other")
+
+	g := NewPackageGraph("go1.18")
+	g.topPkgs = []*packages.Package{root}
+	for _, test := range []struct {
+		pkg  *packages.Package
+		want []string
+	}{
+		{v, []string{"example.com/v", "example.com/a", "example.com/root"}},
+		{c, []string{"example.com/c", "example.com/b", "example.com/root"}},
+		{root, []string{"example.com/root"}},
+		{other, nil},
+	} {
+		if diff := cmp.Diff(test.want, g.importChain(test.pkg)); diff != "" {
+			t.Errorf("importChain(%s) mismatch (-want, +got):\n%s", test.pkg.PkgPath, diff)
+		}
+	}
+}
+
+func TestSBOMModule(t *testing.T) {
+	for _, test := range []struct {
+		mod  *packages.Module
+		want *govulncheck.Module
+	}{
+		{
+			&packages.Module{Path: "golang.org/a", Version: "v1.0.0"},
+			&govulncheck.Module{Path: "golang.org/a", Version: "v1.0.0"},
+		},
+		{
+			&packages.Module{Path: "golang.org/a", Version: "v1.0.0", Replace: &packages.Module{Path: "golang.org/a", Version: "v1.0.1"}},
+			&govulncheck.Module{Path: "golang.org/a", Version: "v1.0.1"},
+		},
+		{
+			&packages.Module{Path: "golang.org/a", Version: "v1.0.0", Replace: &packages.Module{Path: "golang.org/fork", Version: "v1.1.0"}},
+			&govulncheck.Module{Path: "golang.org/fork", Version: "v1.1.0", Replaces: "golang.org/a"},
+		},
+	} {
+		if diff := cmp.Diff(test.want, sbomModule(test.mod)); diff != "" {
+			t.Errorf("sbomModule(%s) mismatch (-want, +got):\n%s", test.mod.Path, diff)
+		}
+	}
+}
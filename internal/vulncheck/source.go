@@ -5,8 +5,13 @@
 package vulncheck
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/packages"
@@ -16,15 +21,149 @@ import (
 	"golang.org/x/vuln/internal/osv"
 )
 
+// A SymbolsError is returned by Source when the call graph of the
+// scanned packages could not be built. The findings for imported
+// packages and required modules were emitted, but calls of vulnerable
+// symbols were not analyzed. The failure was reported to the handler,
+// see emitSymbolsFailed.
+type SymbolsError struct {
+	Err error
+}
+
+func (e *SymbolsError) Error() string {
+	return fmt.Sprintf("building the call graph failed, so calls of vulnerable symbols cannot be analyzed and only vulnerabilities of imported packages and required modules are reported: %v", e.Err)
+}
+
+func (e *SymbolsError) Unwrap() error { return e.Err }
+
 // Source detects vulnerabilities in pkgs and emits the findings to handler.
 func Source(ctx context.Context, handler govulncheck.Handler, cfg *govulncheck.Config, client *client.Client, graph *PackageGraph) error {
-	vr, err := source(ctx, handler, cfg, client, graph)
+	var vr *Result
+	var err error
+	if cfg.FailFast {
+		vr, err = sourceFailFast(ctx, handler, cfg, client, graph)
+	} else {
+		vr, err = source(ctx, handler, cfg, client, graph)
+	}
 	if err != nil {
 		return err
 	}
 
 	if cfg.ScanLevel.WantSymbols() {
-		return emitCallFindings(handler, sourceCallstacks(vr))
+		if cfg.Stream {
+			return nil // already emitted by source
+		}
+		start := time.Now()
+		cs, more := sourceCallstacks(vr, max(cfg.Stacks-1, 0))
+		eps := sourceEntryPoints(vr)
+		if err := EmitTiming(handler, cfg, "compute traces", time.Since(start)); err != nil {
+			return err
+		}
+		if err := emitCallFindings(handler, cs, eps, cfg.EmitEntryPoints, ""); err != nil {
+			return err
+		}
+		return emitAlternativeCallFindings(handler, more, eps, cfg.EmitEntryPoints, "")
+	}
+	return nil
+}
+
+// sourceFailFast is source for a scan with Config.FailFast. The module
+// and package level findings are held back until the reported
+// vulnerability is known, and the result is limited to it.
+func sourceFailFast(ctx context.Context, handler govulncheck.Handler, cfg *govulncheck.Config, client *client.Client, graph *PackageGraph) (*Result, error) {
+	h := &failFastHandler{Handler: handler}
+	vr, err := source(ctx, h, cfg, client, graph)
+	var serr *SymbolsError
+	if err != nil && !errors.As(err, &serr) {
+		return nil, err
+	}
+	var called []*Vuln
+	if err == nil && cfg.ScanLevel.WantSymbols() {
+		called = firstVuln(vr.Vulns)
+		vr.Vulns = called
+	}
+	if err := h.flush(called); err != nil {
+		return nil, err
+	}
+	return vr, err
+}
+
+// failFastHandler wraps a handler and holds back the findings emitted
+// to it, which are module and package level findings, see flush.
+type failFastHandler struct {
+	govulncheck.Handler
+	held []*govulncheck.Finding
+}
+
+func (h *failFastHandler) Finding(f *govulncheck.Finding) error {
+	h.held = append(h.held, f)
+	return nil
+}
+
+// flush emits the findings held back. If a vulnerability is called,
+// the findings of the other vulnerabilities of imported packages are
+// left out, as whether they are called was not analyzed.
+func (h *failFastHandler) flush(called []*Vuln) error {
+	skip := make(map[string]bool)
+	if len(called) > 0 {
+		for _, f := range h.held {
+			if f.Level == govulncheck.ScanLevelPackage && f.OSV != called[0].OSV.ID {
+				skip[f.OSV] = true
+			}
+		}
+	}
+	for _, f := range h.held {
+		if skip[f.OSV] {
+			continue
+		}
+		if err := h.Handler.Finding(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstVuln returns the vulnerability in vulns that sorts first by
+// OSV ID, package and symbol, so that -fail-fast reports the same
+// vulnerability on every run, or nil if vulns is empty.
+func firstVuln(vulns []*Vuln) []*Vuln {
+	if len(vulns) == 0 {
+		return nil
+	}
+	return []*Vuln{slices.MinFunc(vulns, compareVulns)}
+}
+
+// compareVulns orders vulnerabilities by OSV ID, package and symbol.
+func compareVulns(a, b *Vuln) int {
+	return cmp.Or(
+		cmp.Compare(a.OSV.ID, b.OSV.ID),
+		cmp.Compare(a.Package.PkgPath, b.Package.PkgPath),
+		cmp.Compare(a.Symbol, b.Symbol),
+	)
+}
+
+// streamCallFindings emits the symbol level findings of the called
+// vulnerabilities in res, which calledVulnSymbols found together, see
+// Config.Stream. The vulnerabilities are handled in the order of
+// compareVulns, so that the stream is deterministic.
+func streamCallFindings(handler govulncheck.Handler, cfg *govulncheck.Config, res *Result) error {
+	vulns := slices.Clone(res.Vulns)
+	slices.SortFunc(vulns, compareVulns)
+	entries := entrySet(res)
+	for _, vuln := range vulns {
+		stacks := vulnCallstacks(vuln, res, max(cfg.Stacks-1, 0))
+		if len(stacks) == 0 {
+			continue
+		}
+		eps := reachingEntries(vuln.CallSink, entries)
+		// The first call stack is the representative one,
+		// the others are alternatives requested with Stacks.
+		for _, stack := range stacks {
+			updateInitStackPositions(stack)
+			if err := handler.Finding(callFinding(vuln, stack, eps, cfg.EmitEntryPoints, "")); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -41,19 +180,39 @@ func source(ctx context.Context, handler govulncheck.Handler, cfg *govulncheck.C
 	// with fetching vulnerabilities. If the vulns set is empty, return without
 	// waiting for SSA construction or callgraph to finish.
 	var (
-		wg       sync.WaitGroup // guards entries, cg, and buildErr
-		entries  []*ssa.Function
-		cg       *callgraph.Graph
-		buildErr error
+		wg        sync.WaitGroup // guards entries, cg, buildErr, and the build times
+		entries   []*ssa.Function
+		noEntries bool
+		cg        *callgraph.Graph
+		buildErr  error
+		ssaTime   time.Duration
+		cgTime    time.Duration
 	)
 	if cfg.ScanLevel.WantSymbols() {
 		fset := graph.TopPkgs()[0].Fset
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			prog, ssaPkgs := buildSSA(graph.TopPkgs(), fset)
+			// SSA construction and call graph algorithms can panic on
+			// code they do not support. Report this as a build error
+			// so that the results at other levels are not lost.
+			defer func() {
+				if r := recover(); r != nil {
+					buildErr = fmt.Errorf("panic: %v", r)
+				}
+			}()
+			start := time.Now()
+			roots := graph.TopPkgs()
+			if cfg.AsLibrary {
+				roots = libraryPackages(roots)
+			}
+			prog, ssaPkgs := buildSSA(roots, fset)
 			entries = entryPoints(ssaPkgs)
+			noEntries = !hasEntryFunctions(entries)
+			ssaTime = time.Since(start)
+			start = time.Now()
 			cg, buildErr = callGraph(ctx, prog, entries)
+			cgTime = time.Since(start)
 		}()
 	}
 
@@ -65,21 +224,42 @@ func source(ctx context.Context, handler govulncheck.Handler, cfg *govulncheck.C
 		return nil, err
 	}
 
-	mv, err := FetchVulnerabilities(ctx, client, graph.Modules())
+	// With -depth direct, only the direct imports of the top-level
+	// packages, and their modules, are checked for vulnerabilities.
+	var direct map[string]bool // nil if all packages are checked
+	mods := graph.Modules()
+	if cfg.Depth == govulncheck.DepthDirect {
+		direct = graph.directPkgs()
+		mods = graph.pkgModules(direct)
+	}
+
+	start := time.Now()
+	mv, skipped, err := FetchVulnerabilities(ctx, client, skipModules(mods, cfg.SkipModules))
 	if err != nil {
 		return nil, err
 	}
+	if err := emitSkippedNonGo(handler, skipped); err != nil {
+		return nil, err
+	}
+	if err := EmitTiming(handler, cfg, "fetch vulnerabilities", time.Since(start)); err != nil {
+		return nil, err
+	}
 
 	// Emit OSV entries immediately in their raw unfiltered form.
 	if err := emitOSVs(handler, mv); err != nil {
 		return nil, err
 	}
-
+	if err := emitLocalReplaceWarnings(handler, mv); err != nil {
+		return nil, err
+	}
+	if err := emitRangeWarnings(handler, mv); err != nil {
+		return nil, err
+	}
 	if err := handler.Progress(&govulncheck.Progress{Message: checkingSrcVulnsMessage}); err != nil {
 		return nil, err
 	}
 
-	affVulns := affectingVulnerabilities(mv, "", "")
+	affVulns := affectingVulnerabilities(mv, "", "", cfg.Overrides, cfg.AssumedVersions, cfg.MinGoVersion)
 	if err := emitModuleFindings(handler, affVulns); err != nil {
 		return nil, err
 	}
@@ -88,12 +268,17 @@ func source(ctx context.Context, handler govulncheck.Handler, cfg *govulncheck.C
 		return &Result{}, nil
 	}
 
-	impVulns := importedVulnPackages(affVulns, graph)
+	impVulns := importedVulnPackages(affVulns, graph, direct)
 	// Emit information on imported vulnerable packages now as
 	// call graph computation might take a while.
-	if err := emitPackageFindings(handler, impVulns); err != nil {
+	if err := emitPackageFindings(handler, impVulns, graph); err != nil {
 		return nil, err
 	}
+	if cfg.EmitGraph && len(impVulns) > 0 {
+		if err := handler.Graph(graph.importSlice(impVulns)); err != nil {
+			return nil, err
+		}
+	}
 
 	// Return result immediately if not in symbol mode or
 	// if there are no vulnerabilities imported.
@@ -102,21 +287,58 @@ func source(ctx context.Context, handler govulncheck.Handler, cfg *govulncheck.C
 	}
 
 	wg.Wait() // wait for build to finish
+	if noEntries {
+		// Tags excluding the code of the packages leave nothing to
+		// analyze calls from, which would look like a clean result.
+		if err := emitNoEntriesWarning(handler, cfg.Tags); err != nil {
+			return nil, err
+		}
+	}
 	if buildErr != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Degrade to the package level results.
+		serr := &SymbolsError{Err: buildErr}
+		if err := emitSymbolsFailed(handler, serr); err != nil {
+			return nil, err
+		}
+		return nil, serr
+	}
+	if err := EmitTiming(handler, cfg, "build SSA", ssaTime); err != nil {
+		return nil, err
+	}
+	if err := EmitTiming(handler, cfg, "build call graph", cgTime); err != nil {
 		return nil, err
 	}
 
-	entryFuncs, callVulns := calledVulnSymbols(entries, affVulns, cg, graph)
+	if cfg.HideGenerated {
+		pruneGenerated(cg, graph)
+	}
+
+	start = time.Now()
+	var found func(*Result) error
+	if cfg.Stream {
+		found = func(res *Result) error { return streamCallFindings(handler, cfg, res) }
+	}
+	entryFuncs, callVulns, err := calledVulnSymbols(ctx, entries, affVulns, cg, graph, direct, cfg.FailFast, found)
+	if err != nil {
+		return nil, err
+	}
+	if err := EmitTiming(handler, cfg, "analyze calls", time.Since(start)); err != nil {
+		return nil, err
+	}
 	return &Result{EntryFunctions: entryFuncs, Vulns: callVulns}, nil
 }
 
 // importedVulnPackages detects imported vulnerable packages.
-func importedVulnPackages(affVulns affectingVulns, graph *PackageGraph) []*Vuln {
+// If direct is not nil, only the packages in it are checked.
+func importedVulnPackages(affVulns affectingVulns, graph *PackageGraph, direct map[string]bool) []*Vuln {
 	var vulns []*Vuln
 	analyzed := make(map[*packages.Package]bool) // skip analyzing the same package multiple times
 	var vulnImports func(pkg *packages.Package)
 	vulnImports = func(pkg *packages.Package) {
-		if analyzed[pkg] {
+		if analyzed[pkg] || (direct != nil && !direct[pkg.PkgPath]) {
 			return
 		}
 
@@ -126,6 +348,7 @@ func importedVulnPackages(affVulns affectingVulns, graph *PackageGraph) []*Vuln
 			vuln := &Vuln{
 				OSV:     osv,
 				Package: graph.GetPackage(pkg.PkgPath),
+				Version: affVulns.version(pkgModPath(pkg), pkg.PkgPath, osv.ID),
 			}
 			vulns = append(vulns, vuln)
 		}
@@ -148,9 +371,52 @@ func importedVulnPackages(affVulns affectingVulns, graph *PackageGraph) []*Vuln
 // A slice of call graph is computed related to the reachable vulnerabilities. Each
 // reachable Vuln has attached FuncNode that can be upward traversed to the entry points.
 // Entry points that reach the vulnerable symbols are also returned.
-func calledVulnSymbols(sources []*ssa.Function, affVulns affectingVulns, cg *callgraph.Graph, graph *PackageGraph) ([]*FuncNode, []*Vuln) {
-	sinksWithVulns := vulnFuncs(cg, affVulns, graph)
+// If direct is not nil, only vulnerable symbols of packages in direct
+// are considered. If first is set, the call graph is only sliced for
+// the called vulnerability with the smallest OSV ID, see firstCalledSinks
+// and Config.FailFast.
+//
+// If found is not nil, the call graph is sliced for one vulnerability
+// at a time, in the order of OSV IDs, and found is called with the
+// result for each vulnerability as soon as it is known to be called,
+// see Config.Stream. The results are then not connected to each other.
+func calledVulnSymbols(ctx context.Context, sources []*ssa.Function, affVulns affectingVulns, cg *callgraph.Graph, graph *PackageGraph, direct map[string]bool, first bool, found func(*Result) error) ([]*FuncNode, []*Vuln, error) {
+	links := graphLinknames(graph)
+	sinksWithVulns := vulnFuncs(cg, affVulns, graph, links, direct)
+	if first {
+		var err error
+		sinksWithVulns, err = firstCalledSinks(ctx, sources, sinksWithVulns)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if found == nil {
+		return sliceCalledVulns(ctx, sources, sinksWithVulns, affVulns, graph, links)
+	}
 
+	var entries []*FuncNode
+	var vulns []*Vuln
+	ids, byID := sinksByID(sinksWithVulns)
+	for _, id := range ids {
+		e, vs, err := sliceCalledVulns(ctx, sources, byID[id], affVulns, graph, links)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(vs) == 0 {
+			continue // not called
+		}
+		if err := found(&Result{EntryFunctions: e, Vulns: vs}); err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, e...)
+		vulns = append(vulns, vs...)
+	}
+	return entries, vulns, nil
+}
+
+// sliceCalledVulns returns the entry points among sources and the
+// vulnerabilities of the sinks called from them, see calledVulnSymbols.
+func sliceCalledVulns(ctx context.Context, sources []*ssa.Function, sinksWithVulns map[*callgraph.Node][]*osv.Entry, affVulns affectingVulns, graph *PackageGraph, links linknames) ([]*FuncNode, []*Vuln, error) {
 	// Compute call graph backwards reachable
 	// from vulnerable functions and methods.
 	var sinks []*callgraph.Node
@@ -158,6 +424,9 @@ func calledVulnSymbols(sources []*ssa.Function, affVulns affectingVulns, cg *cal
 		sinks = append(sinks, n)
 	}
 	bcg := callGraphSlice(sinks, false)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 
 	// Interesect backwards call graph with forward
 	// reachable graph to remove redundant edges.
@@ -168,6 +437,9 @@ func calledVulnSymbols(sources []*ssa.Function, affVulns affectingVulns, cg *cal
 		}
 	}
 	fcg := callGraphSlice(filteredSources, true)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 
 	// Get the sinks that are in fact reachable from entry points.
 	filteredSinks := make(map[*callgraph.Node][]*osv.Entry)
@@ -179,7 +451,96 @@ func calledVulnSymbols(sources []*ssa.Function, affVulns affectingVulns, cg *cal
 
 	// Transform the resulting call graph slice into
 	// vulncheck representation.
-	return vulnCallGraph(filteredSources, filteredSinks, graph)
+	entries, vulns := vulnCallGraph(filteredSources, filteredSinks, graph, links)
+	for _, v := range vulns {
+		if v.Package != nil {
+			v.Version = affVulns.version(pkgModPath(v.Package), v.Package.PkgPath, v.OSV.ID)
+		}
+	}
+	return entries, vulns, nil
+}
+
+// sinksByID returns the sorted OSV IDs of the vulnerabilities of sinks
+// and, for each of them, the sinks with only that vulnerability attached.
+func sinksByID(sinks map[*callgraph.Node][]*osv.Entry) ([]string, map[string]map[*callgraph.Node][]*osv.Entry) {
+	var ids []string
+	byID := make(map[string]map[*callgraph.Node][]*osv.Entry)
+	for n, osvs := range sinks {
+		for _, o := range osvs {
+			if byID[o.ID] == nil {
+				ids = append(ids, o.ID)
+				byID[o.ID] = make(map[*callgraph.Node][]*osv.Entry)
+			}
+			byID[o.ID][n] = append(byID[o.ID][n], o)
+		}
+	}
+	slices.Sort(ids)
+	return ids, byID
+}
+
+// firstCalledSinks returns the sinks of the vulnerability with the
+// smallest OSV ID in sinks that is called from sources, with only that
+// vulnerability attached, or nil if none is called.
+//
+// The vulnerabilities are tried in order, searching the callers of
+// their sinks backwards until one of sources is reached. The search
+// stops at the first one reached, and functions visited by the failed
+// searches for the previous vulnerabilities are not visited again, as
+// they cannot be called from sources. This makes -fail-fast much
+// cheaper than slicing the call graph for all vulnerabilities.
+func firstCalledSinks(ctx context.Context, sources []*ssa.Function, sinks map[*callgraph.Node][]*osv.Entry) (map[*callgraph.Node][]*osv.Entry, error) {
+	ids, byID := sinksByID(sinks)
+	isSource := make(map[*ssa.Function]bool)
+	for _, f := range sources {
+		isSource[f] = true
+	}
+	visited := make(map[*callgraph.Node]bool)
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var starts []*callgraph.Node
+		for n := range byID[id] {
+			starts = append(starts, n)
+		}
+		if calledFrom(starts, isSource, visited) {
+			return byID[id], nil
+		}
+	}
+	return nil, nil
+}
+
+// calledFrom reports whether one of starts is called, directly or
+// transitively, from a function in sources. Nodes in visited are
+// skipped, and the nodes searched are added to it.
+func calledFrom(starts []*callgraph.Node, sources map[*ssa.Function]bool, visited map[*callgraph.Node]bool) bool {
+	stack := slices.Clone(starts)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+		for _, e := range n.In {
+			if sources[e.Caller.Func] {
+				return true
+			}
+			stack = append(stack, e.Caller)
+		}
+	}
+	return false
+}
+
+// pruneGenerated removes the functions declared in generated files
+// of the top-level packages from cg, so that vulnerable symbols
+// reachable only through generated code are not reported.
+func pruneGenerated(cg *callgraph.Graph, graph *PackageGraph) {
+	for f, n := range cg.Nodes {
+		if f != nil && n != cg.Root && graph.isGenerated(funcPosition(f).Filename) {
+			cg.DeleteNode(n)
+		}
+	}
 }
 
 // callGraphSlice computes a slice of callgraph beginning at starts
@@ -222,7 +583,8 @@ func callGraphSlice(starts []*callgraph.Node, forward bool) *callgraph.Graph {
 }
 
 // vulnCallGraph creates vulnerability call graph in terms of sources and sinks.
-func vulnCallGraph(sources []*callgraph.Node, sinks map[*callgraph.Node][]*osv.Entry, graph *PackageGraph) ([]*FuncNode, []*Vuln) {
+// Sinks linked to a vulnerable function by links are reported as calling it.
+func vulnCallGraph(sources []*callgraph.Node, sinks map[*callgraph.Node][]*osv.Entry, graph *PackageGraph, links linknames) ([]*FuncNode, []*Vuln) {
 	var entries []*FuncNode
 	var vulns []*Vuln
 	nodes := make(map[*ssa.Function]*FuncNode)
@@ -237,6 +599,26 @@ func vulnCallGraph(sources []*callgraph.Node, sinks map[*callgraph.Node][]*osv.E
 		f := s.Func
 		funNode := createNode(nodes, s.Func, graph)
 
+		if t, ok := links.target(f); ok {
+			// The vulnerable symbol is the one f is linked to.
+			sink := &FuncNode{
+				Name:     t.name,
+				RecvType: t.recvType(),
+				Package:  graph.GetPackage(t.pkg),
+				Pos:      funNode.Pos,
+				CallSites: []*CallSite{{
+					Parent:   funNode,
+					Name:     t.name,
+					Resolved: true,
+					Pos:      funNode.Pos,
+				}},
+			}
+			for _, osv := range osvs {
+				vulns = append(vulns, calledVuln(sink, osv, t.symbol(), sink.Package))
+			}
+			continue
+		}
+
 		// Populate CallSink field for each detected vuln symbol.
 		for _, osv := range osvs {
 			vulns = append(vulns, calledVuln(funNode, osv, dbFuncName(f), funNode.Package))
@@ -275,12 +657,20 @@ func vulnCallGraph(sources []*callgraph.Node, sinks map[*callgraph.Node][]*osv.E
 	return entries, vulns
 }
 
-// vulnFuncs returns vulnerability information for vulnerable functions in cg.
-func vulnFuncs(cg *callgraph.Graph, affVulns affectingVulns, graph *PackageGraph) map[*callgraph.Node][]*osv.Entry {
+// vulnFuncs returns vulnerability information for vulnerable functions in cg,
+// including functions linked to vulnerable functions by links. If direct
+// is not nil, only functions of packages in direct are considered.
+func vulnFuncs(cg *callgraph.Graph, affVulns affectingVulns, graph *PackageGraph, links linknames, direct map[string]bool) map[*callgraph.Node][]*osv.Entry {
 	m := make(map[*callgraph.Node][]*osv.Entry)
 	for f, n := range cg.Nodes {
-		p := pkgPath(f)
-		vulns := affVulns.ForSymbol(pkgModPath(graph.GetPackage(p)), p, dbFuncName(f))
+		p, sym := pkgPath(f), dbFuncName(f)
+		if t, ok := links.target(f); ok {
+			p, sym = t.pkg, t.symbol()
+		}
+		if direct != nil && !direct[p] {
+			continue
+		}
+		vulns := affVulns.ForSymbol(pkgModPath(graph.GetPackage(p)), p, sym)
 		if len(vulns) > 0 {
 			m[n] = vulns
 		}
@@ -298,6 +688,7 @@ func createNode(nodes map[*ssa.Function]*FuncNode, f *ssa.Function, graph *Packa
 		RecvType: funcRecvType(f),
 		Pos:      funcPosition(f),
 	}
+	fn.Generated = graph.isGenerated(fn.Pos.Filename)
 	nodes[f] = fn
 	return fn
 }
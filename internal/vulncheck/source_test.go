@@ -8,9 +8,16 @@ import (
 	"context"
 	"path"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/packages/packagestest"
+	"golang.org/x/tools/go/ssa"
 	"golang.org/x/vuln/internal/client"
 	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
@@ -389,7 +396,8 @@ func TestNoSyntheticNodes(t *testing.T) {
 		t.Fatalf("expected VulnData.Vuln1 as called symbol; got %s", vuln.Symbol)
 	}
 
-	stack := sourceCallstacks(result)[vuln]
+	stacks, _ := sourceCallstacks(result, 0)
+	stack := stacks[vuln]
 	// We don't want the call stack X -> *VulnData.Vuln1 (wrapper) -> VulnData.Vuln1.
 	// We want X -> VulnData.Vuln1.
 	if len(stack) != 2 {
@@ -519,3 +527,656 @@ func TestIssue57174(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestGenericVulnSymbols checks that vulnerable symbols that are
+// generic, or called through generic wrappers, are attributed to
+// their base name in the vulnerability database.
+func TestGenericVulnSymbols(t *testing.T) {
+	e := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import (
+				"golang.org/amod/avuln"
+				"golang.org/bmod/bvuln"
+			)
+
+			func X() {
+				wrap(1)
+				avuln.VulnData[string]{}.Vuln1()
+			}
+
+			func wrap[T any](t T) {
+				bvuln.Vuln(t)
+			}
+			`,
+			},
+		},
+		{
+			Name: "golang.org/amod@v1.1.3",
+			Files: map[string]interface{}{"avuln/avuln.go": `
+			package avuln
+
+			type VulnData[T any] struct {}
+			func (v VulnData[T]) Vuln1() {}
+			`},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln[T any](t T) {}
+			`},
+		},
+	})
+	defer e.Cleanup()
+
+	graph := NewPackageGraph("go1.18")
+	err := graph.LoadPackagesAndMods(e.Config, nil, []string{path.Join(e.Temp(), "entry/x")}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &govulncheck.Config{ScanLevel: "symbol"}
+	handler := test.NewMockHandler()
+	if err := Source(context.Background(), handler, cfg, c, graph); err != nil {
+		t.Fatal(err)
+	}
+
+	// Collect the vulnerable symbols of symbol-level findings.
+	got := make(map[string]bool)
+	for _, f := range handler.FindingMessages {
+		if len(f.Trace) < 2 {
+			continue // not a symbol-level finding
+		}
+		sink := f.Trace[0]
+		got[f.OSV+":"+sink.Package+"."+symbol(sink)] = true
+	}
+	want := map[string]bool{
+		"VA:golang.org/amod/avuln.VulnData.Vuln1": true,
+		"VB:golang.org/bmod/bvuln.Vuln":           true,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+// symbol returns the database name of the function of frame.
+func symbol(frame *govulncheck.Frame) string {
+	if frame.Receiver == "" {
+		return frame.Function
+	}
+	recv, _, _ := strings.Cut(strings.TrimPrefix(frame.Receiver, "*"), "[")
+	if i := strings.LastIndex(recv, "."); i >= 0 {
+		recv = recv[i+1:]
+	}
+	return recv + "." + frame.Function
+}
+
+// TestConcurrentSource checks that independent analyses can run
+// concurrently, sharing the client and package loading config.
+// Run with -race to detect state shared between analyses.
+func TestConcurrentSource(t *testing.T) {
+	e := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import "golang.org/bmod/bvuln"
+
+			func X() {
+				bvuln.Vuln()
+			}
+			`,
+			},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	})
+	defer e.Cleanup()
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 4
+	results := make([]*Result, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			graph := NewPackageGraph("go1.18")
+			if err := graph.LoadPackagesAndMods(e.Config, nil, []string{path.Join(e.Temp(), "entry/x")}, true); err != nil {
+				t.Error(err)
+				return
+			}
+			cfg := &govulncheck.Config{ScanLevel: "symbol"}
+			r, err := source(context.Background(), test.NewMockHandler(), cfg, c, graph)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+
+	for i, r := range results {
+		if r == nil || len(r.Vulns) != 1 || r.Vulns[0].Symbol != "Vuln" {
+			t.Errorf("analysis %d: want a single call to bvuln.Vuln, got %v", i, r)
+		}
+	}
+}
+
+// TestWholeModuleVuln checks that vulnerabilities without package
+// information are reported for every imported package of the module.
+func TestWholeModuleVuln(t *testing.T) {
+	graph := loadTestGraph(t, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import "golang.org/bmod/bvuln"
+
+			func X() {
+				bvuln.NoVuln()
+			}
+			`,
+			},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func NoVuln() {}
+			`},
+		},
+	}, nil, "entry/x", false)
+
+	// The entry only specifies the module.
+	c, err := client.NewInMemoryClient([]*osv.Entry{{
+		ID: "VM",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "golang.org/bmod"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver}},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := runTestSource(t, &govulncheck.Config{ScanLevel: "package"}, c, graph)
+
+	var got []string
+	for _, f := range handler.FindingMessages {
+		fr := f.Trace[0]
+		got = append(got, f.OSV+":"+fr.Module+":"+fr.Package)
+	}
+	want := []string{
+		"VM:golang.org/bmod:",
+		"VM:golang.org/bmod:golang.org/bmod/bvuln",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestLinknameVulnSymbols(t *testing.T) {
+	graph := loadTestGraph(t, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import (
+				_ "unsafe"
+
+				_ "golang.org/bmod/bvuln"
+			)
+
+			//go:linkname vuln golang.org/bmod/bvuln.Vuln
+			func vuln()
+
+			func X() {
+				vuln()
+			}
+			`,
+			},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	}, nil, "entry/x", true)
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := runTestSource(t, &govulncheck.Config{ScanLevel: "symbol"}, c, graph)
+	got := symbolTraces(handler)
+	want := [][]string{{
+		"golang.org/bmod/bvuln.Vuln",
+		"golang.org/entry/x.vuln",
+		"golang.org/entry/x.X",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestMethodValueVulnSymbols(t *testing.T) {
+	graph := loadTestGraph(t, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import "golang.org/amod/avuln"
+
+			func call(f func()) { f() }
+
+			func apply(f func(avuln.VulnData), v avuln.VulnData) { f(v) }
+
+			func X() {
+				v := avuln.VulnData{}
+				call(v.Vuln1) // method value
+			}
+
+			func Y() {
+				apply(avuln.VulnData.Vuln2, avuln.VulnData{}) // method expression
+			}`,
+			},
+		},
+		{
+			Name: "golang.org/amod@v1.1.3",
+			Files: map[string]interface{}{"avuln/avuln.go": `
+			package avuln
+
+			type VulnData struct {}
+			func (v VulnData) Vuln1() {}
+			func (v VulnData) Vuln2() {}
+			`},
+		},
+	}, nil, "entry/x", true)
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := runTestSource(t, &govulncheck.Config{ScanLevel: "symbol"}, c, graph)
+	got := symbolTraces(handler)
+	sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+	// The bound and thunk wrappers ssa creates for the
+	// method value and expression are not part of the traces.
+	want := [][]string{
+		{"golang.org/amod/avuln.VulnData.Vuln1", "golang.org/entry/x.call", "golang.org/entry/x.X"},
+		{"golang.org/amod/avuln.VulnData.Vuln2", "golang.org/entry/x.apply", "golang.org/entry/x.Y"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDirectDepth(t *testing.T) {
+	graph := loadTestGraph(t, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import (
+				"golang.org/amod/avuln"
+				"golang.org/cmod/c"
+			)
+
+			func X() {
+				avuln.VulnData{}.Vuln1()
+				c.C()
+			}`,
+			},
+		},
+		{
+			Name: "golang.org/amod@v1.1.3",
+			Files: map[string]interface{}{"avuln/avuln.go": `
+			package avuln
+
+			type VulnData struct {}
+			func (v VulnData) Vuln1() {}
+			func (v VulnData) Vuln2() {}
+			`},
+		},
+		{
+			Name: "golang.org/cmod@v1.0.0",
+			Files: map[string]interface{}{"c/c.go": `
+			package c
+
+			import "golang.org/bmod/bvuln"
+
+			func C() { bvuln.Vuln() }
+			`},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	}, nil, "entry/x", true)
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		depth string
+		want  []string
+	}{
+		{"", []string{"STD module", "VA module", "VA package", "VA symbol", "VB module", "VB package", "VB symbol"}},
+		// The vulnerable package of VB is only imported indirectly.
+		{govulncheck.DepthDirect, []string{"VA module", "VA package", "VA symbol"}},
+	} {
+		handler := runTestSource(t, &govulncheck.Config{ScanLevel: "symbol", Depth: tc.depth}, c, graph)
+		var got []string
+		for _, f := range handler.FindingMessages {
+			got = append(got, f.OSV+" "+string(f.Level))
+		}
+		sort.Strings(got)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("depth %q: mismatch (-want, +got):\n%s", tc.depth, diff)
+		}
+	}
+}
+
+func TestFirstCalledSinks(t *testing.T) {
+	// main calls v2, and v1 is only called by the unreachable f.
+	cg := &callgraph.Graph{Nodes: make(map[*ssa.Function]*callgraph.Node)}
+	main, f, v1, v2 := &ssa.Function{}, &ssa.Function{}, &ssa.Function{}, &ssa.Function{}
+	callgraph.AddEdge(cg.CreateNode(main), nil, cg.CreateNode(v2))
+	callgraph.AddEdge(cg.CreateNode(f), nil, cg.CreateNode(v1))
+	o1, o2 := &osv.Entry{ID: "GO-0000-0001"}, &osv.Entry{ID: "GO-0000-0002"}
+	sinks := map[*callgraph.Node][]*osv.Entry{
+		cg.Nodes[v1]: {o1},
+		cg.Nodes[v2]: {o2},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		sources []*ssa.Function
+		want    map[*callgraph.Node][]*osv.Entry
+	}{
+		{"one called", []*ssa.Function{main}, map[*callgraph.Node][]*osv.Entry{cg.Nodes[v2]: {o2}}},
+		{"both called", []*ssa.Function{main, f}, map[*callgraph.Node][]*osv.Entry{cg.Nodes[v1]: {o1}}},
+		{"none called", nil, nil},
+	} {
+		got, err := firstCalledSinks(context.Background(), tc.sources, sinks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := firstCalledSinks(ctx, []*ssa.Function{main}, sinks); err == nil {
+		t.Error("no error for a cancelled context")
+	}
+}
+
+func TestNoEntriesWarning(t *testing.T) {
+	modules := []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"main.go": `
+			//go:build prod
+
+			package main
+
+			func main() {}
+			`,
+				"doc.go": `
+			// Package main is only built with the prod tag.
+			package main
+
+			import _ "golang.org/bmod/bvuln"
+			`,
+				"lib/lib.go": `
+			//go:build prod
+
+			package lib
+
+			func Do() {}
+			`,
+				"lib/doc.go": `
+			package lib
+
+			import _ "golang.org/bmod/bvuln"
+			`,
+			},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	}
+
+	const want = "warning: the scanned packages have no entry functions with build tags dev, so calls from them cannot be found; the tags might exclude the files declaring them"
+	vulnClient, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without imported vulnerabilities, calls are not
+	// analyzed and the lack of entry functions does not matter.
+	cleanClient, err := client.NewInMemoryClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		tags    []string
+		pattern string
+		c       *client.Client
+		warn    bool
+	}{
+		{[]string{"dev"}, "entry", vulnClient, true},
+		{[]string{"dev"}, "entry/lib", vulnClient, true},
+		{[]string{"dev"}, "entry", cleanClient, false},
+		{[]string{"prod"}, "entry", vulnClient, false},
+		{[]string{"prod"}, "entry/lib", vulnClient, false},
+	} {
+		graph := loadTestGraph(t, modules, tc.tags, tc.pattern, true)
+		c := tc.c
+		cfg := &govulncheck.Config{ScanLevel: govulncheck.ScanLevelSymbol, Tags: tc.tags}
+		handler := runTestSource(t, cfg, c, graph)
+		warned := false
+		for _, p := range handler.ProgressMessages {
+			warned = warned || p.Message == want
+		}
+		if warned != tc.warn {
+			t.Errorf("%s with tags %v: warned = %t, want %t", tc.pattern, tc.tags, warned, tc.warn)
+		}
+	}
+}
+
+func TestAlternativeStackFindings(t *testing.T) {
+	graph := loadTestGraph(t, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import "golang.org/bmod/bvuln"
+
+			func X() {
+				bvuln.Vuln()
+			}
+
+			func Y() {
+				bvuln.Vuln()
+			}`,
+			},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	}, nil, "entry/x", true)
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stream := range []bool{false, true} {
+		cfg := &govulncheck.Config{ScanLevel: "symbol", Stacks: 2, EmitEntryPoints: true, Stream: stream}
+		handler := runTestSource(t, cfg, c, graph)
+		traces := symbolTraces(handler)
+		if len(traces) != 2 {
+			t.Fatalf("stream=%t: got %d call stacks, want 2: %v", stream, len(traces), traces)
+		}
+		// The alternative call stack has the same
+		// entry points as the representative one.
+		for _, f := range handler.FindingMessages {
+			if len(f.Trace) < 2 {
+				continue
+			}
+			var eps []string
+			for _, fr := range f.EntryPoints {
+				eps = append(eps, fr.Package+"."+fr.Function)
+			}
+			want := []string{"golang.org/entry/x.X", "golang.org/entry/x.Y"}
+			if f.EntryPointCount != 2 || !reflect.DeepEqual(eps, want) {
+				t.Errorf("stream=%t: trace %s: entry points %d %v, want 2 %v", stream, f.Trace[len(f.Trace)-1].Function, f.EntryPointCount, eps, want)
+			}
+		}
+	}
+}
+
+// timingOrderHandler counts the symbol level findings
+// emitted before the "analyze calls" timing.
+type timingOrderHandler struct {
+	*test.MockHandler
+	analyzed bool
+	early    int
+}
+
+func (h *timingOrderHandler) Finding(f *govulncheck.Finding) error {
+	if len(f.Trace) > 1 && !h.analyzed {
+		h.early++
+	}
+	return h.MockHandler.Finding(f)
+}
+
+func (h *timingOrderHandler) Timing(t *govulncheck.Timing) error {
+	h.analyzed = h.analyzed || t.Phase == "analyze calls"
+	return h.MockHandler.Timing(t)
+}
+
+func TestStreamBeforeCallAnalysis(t *testing.T) {
+	graph := loadTestGraph(t, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import (
+				"golang.org/amod/avuln"
+				"golang.org/bmod/bvuln"
+			)
+
+			func X() {
+				avuln.VulnData{}.Vuln1()
+				bvuln.Vuln()
+			}`,
+			},
+		},
+		{
+			Name: "golang.org/amod@v1.1.3",
+			Files: map[string]interface{}{"avuln/avuln.go": `
+			package avuln
+
+			type VulnData struct {}
+			func (v VulnData) Vuln1() {}
+			`},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	}, nil, "entry/x", true)
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stream := range []bool{false, true} {
+		cfg := &govulncheck.Config{ScanLevel: "symbol", EmitTimings: true, Stream: stream}
+		handler := &timingOrderHandler{MockHandler: test.NewMockHandler()}
+		if err := Source(context.Background(), handler, cfg, c, graph); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(symbolTraces(handler.MockHandler)); got != 2 {
+			t.Fatalf("stream=%t: got %d call stacks, want 2", stream, got)
+		}
+		// Streamed findings do not wait for the
+		// calls of all vulnerabilities to be analyzed.
+		want := 0
+		if stream {
+			want = 2
+		}
+		if handler.early != want {
+			t.Errorf("stream=%t: %d findings before the call analysis ended, want %d", stream, handler.early, want)
+		}
+	}
+}
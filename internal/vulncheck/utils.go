@@ -230,7 +230,89 @@ func FixedVersion(modulePath, version string, affected []osv.Affected) string {
 	if fixed != "" && !strings.HasPrefix(fixed, "v") {
 		fixed = "v" + fixed
 	}
-	return fixed
+	// Databases may omit the +incompatible suffix of fixes.
+	return semver.Incompatible(fixed, version)
+}
+
+// FixedVersions returns all the versions of modulePath in which
+// a vulnerability described by affected has been fixed, sorted
+// increasingly. Like for FixedVersion, the versions have a "v"
+// prefix and, if version is +incompatible, the suffix as well.
+func FixedVersions(modulePath, version string, affected []osv.Affected) []string {
+	seen := make(map[string]bool)
+	var fixes []string
+	for _, a := range affected {
+		if a.Module.Path != modulePath {
+			continue
+		}
+		for _, r := range a.Ranges {
+			if !semver.IsVersionRange(r) {
+				continue
+			}
+			for _, e := range r.Events {
+				fix := e.Fixed
+				if fix == "" {
+					continue
+				}
+				if !strings.HasPrefix(fix, "v") {
+					fix = "v" + fix
+				}
+				fix = semver.Incompatible(fix, version)
+				if !seen[fix] {
+					seen[fix] = true
+					fixes = append(fixes, fix)
+				}
+			}
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool { return semver.Less(fixes[i], fixes[j]) })
+	return fixes
+}
+
+// IntroducedVersion returns the version of modulePath in which the
+// vulnerability described by affected was introduced for version, that
+// is the start of the earliest affected range containing version. An
+// OSV introduced value of "0", meaning the vulnerability is present
+// from the first version, is reported as "v0.0.0". The result is empty
+// if version is not in any semver range. Like for FixedVersion, the
+// version has a "v" prefix.
+func IntroducedVersion(modulePath, version string, affected []osv.Affected) string {
+	var introduced string
+	for _, a := range affected {
+		if a.Module.Path != modulePath {
+			continue
+		}
+		for _, r := range a.Ranges {
+			in := rangeIntroduced(r, version)
+			if in != "" && (introduced == "" || semver.Less(in, introduced)) {
+				introduced = in
+			}
+		}
+	}
+	if introduced != "" && !strings.HasPrefix(introduced, "v") {
+		introduced = "v" + introduced
+	}
+	return introduced
+}
+
+// rangeIntroduced returns the introduced event of the interval of
+// semver range r containing version, if any. For well-formed ranges,
+// this is the latest introduced event at or before version.
+func rangeIntroduced(r osv.Range, version string) string {
+	if !semver.ContainsSemver(r, version) {
+		return ""
+	}
+	introduced := "0.0.0" // a range without events affects all versions
+	for _, e := range r.Events {
+		in := e.Introduced
+		if in == "" || in == "0" || semver.Less(version, in) {
+			continue
+		}
+		if semver.Less(introduced, in) {
+			introduced = in
+		}
+	}
+	return introduced
 }
 
 // earliestValidFix returns the earliest fix for version of modulePath that
@@ -267,7 +349,7 @@ func validFixes(version string, affected []osv.Affected) []string {
 	var fixes []string
 	for _, a := range affected {
 		for _, r := range a.Ranges {
-			if r.Type != osv.RangeTypeSemver {
+			if !semver.IsVersionRange(r) {
 				continue
 			}
 			for _, e := range r.Events {
@@ -303,6 +385,22 @@ func modPath(mod *packages.Module) string {
 	return mod.Path
 }
 
+// isLocalReplace reports whether mod is replaced by a directory
+// on the local file system, which has no version.
+func isLocalReplace(mod *packages.Module) bool {
+	return mod.Replace != nil && mod.Replace.Version == ""
+}
+
+// lookupPath returns the module path under which the vulnerabilities
+// of mod are looked up: the path of its replacement, if any, unless
+// that is a local directory, which is a modified copy of mod.
+func lookupPath(mod *packages.Module) string {
+	if mod.Replace != nil && !isLocalReplace(mod) {
+		return mod.Replace.Path
+	}
+	return mod.Path
+}
+
 func modVersion(mod *packages.Module) string {
 	if mod.Replace != nil {
 		return mod.Replace.Version
@@ -179,6 +179,26 @@ func TestFixedVersion(t *testing.T) {
 			},
 			want: "v1.4.1",
 		},
+		{
+			name:    "incompatible",
+			module:  "example.com/module",
+			version: "v2.0.0+incompatible",
+			in: []osv.Affected{
+				{
+					Module: osv.Module{
+						Path: "example.com/module",
+					},
+					Ranges: []osv.Range{
+						{
+							Type: osv.RangeTypeSemver,
+							Events: []osv.RangeEvent{
+								{Introduced: "0"}, {Fixed: "2.3.0"},
+							},
+						}},
+				},
+			},
+			want: "v2.3.0+incompatible",
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			got := FixedVersion(test.module, test.version, test.in)
@@ -189,6 +209,110 @@ func TestFixedVersion(t *testing.T) {
 	}
 }
 
+func TestFixedVersions(t *testing.T) {
+	semverRange := func(events ...osv.RangeEvent) []osv.Range {
+		return []osv.Range{{Type: osv.RangeTypeSemver, Events: events}}
+	}
+	in := []osv.Affected{
+		{
+			Module: osv.Module{Path: "example.com/module"},
+			Ranges: semverRange(
+				osv.RangeEvent{Introduced: "1.5.0"}, osv.RangeEvent{Fixed: "1.5.2"},
+				osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.4.7"},
+			),
+		},
+		{
+			Module: osv.Module{Path: "example.com/module"},
+			Ranges: semverRange(
+				osv.RangeEvent{Introduced: "1.6.0"}, osv.RangeEvent{Fixed: "v1.6.1"},
+				osv.RangeEvent{Introduced: "1.7.0"}, osv.RangeEvent{Fixed: "v1.5.2"},
+			),
+		},
+		{
+			Module: osv.Module{Path: "example.com/other"},
+			Ranges: semverRange(osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "0.1.0"}),
+		},
+		{
+			Module: osv.Module{Path: "example.com/module"},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeType("unspecified"),
+				Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "v2.0.0"}},
+			}},
+		},
+		{
+			Module: osv.Module{Path: "example.com/incompatible"},
+			Ranges: semverRange(
+				osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.2.0"},
+				osv.RangeEvent{Introduced: "2.0.0"}, osv.RangeEvent{Fixed: "2.1.0"},
+				osv.RangeEvent{Introduced: "3.0.0"}, osv.RangeEvent{Fixed: "3.0.1+incompatible"},
+			),
+		},
+	}
+
+	for _, test := range []struct {
+		module  string
+		version string
+		want    []string
+	}{
+		{"example.com/module", "v1.5.0", []string{"v1.4.7", "v1.5.2", "v1.6.1"}},
+		{"example.com/other", "", []string{"v0.1.0"}},
+		{"example.com/none", "", nil},
+		{"example.com/incompatible", "v2.0.0+incompatible", []string{"v1.2.0", "v2.1.0+incompatible", "v3.0.1+incompatible"}},
+		{"example.com/incompatible", "", []string{"v1.2.0", "v2.1.0", "v3.0.1+incompatible"}},
+	} {
+		t.Run(test.module+"@"+test.version, func(t *testing.T) {
+			got := FixedVersions(test.module, test.version, in)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIntroducedVersion(t *testing.T) {
+	semverRange := func(events ...osv.RangeEvent) []osv.Range {
+		return []osv.Range{{Type: osv.RangeTypeSemver, Events: events}}
+	}
+	in := []osv.Affected{
+		{
+			Module: osv.Module{Path: "example.com/module"},
+			Ranges: semverRange(
+				osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.4.7"},
+				osv.RangeEvent{Introduced: "1.5.0"}, osv.RangeEvent{Fixed: "1.5.2"},
+				osv.RangeEvent{Introduced: "1.7.0"},
+			),
+		},
+		{
+			Module: osv.Module{Path: "example.com/other"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver}},
+		},
+		{
+			Module: osv.Module{Path: "stdlib"},
+			Ranges: semverRange(osv.RangeEvent{Introduced: "1.20.0"}, osv.RangeEvent{Fixed: "1.20.5"}),
+		},
+	}
+
+	for _, test := range []struct {
+		module, version string
+		want            string
+	}{
+		{"example.com/module", "v1.0.0", "v0.0.0"},
+		{"example.com/module", "v1.5.1", "v1.5.0"},
+		{"example.com/module", "v1.6.0", ""},
+		{"example.com/module", "v1.8.0", "v1.7.0"},
+		{"example.com/other", "v1.0.0", "v0.0.0"},
+		{"stdlib", "v1.20.3", "v1.20.0"},
+		{"example.com/none", "v1.0.0", ""},
+	} {
+		t.Run(test.module+"@"+test.version, func(t *testing.T) {
+			got := IntroducedVersion(test.module, test.version, in)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
 func TestDbSymbolName(t *testing.T) {
 	e := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
 		{
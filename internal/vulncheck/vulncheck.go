@@ -60,6 +60,11 @@ type Vuln struct {
 	// When the package of symbol is not imported, Package will be
 	// unavailable and set to nil.
 	Package *packages.Package
+
+	// Version is the version of the module of Package that OSV
+	// affects, if it is not the version of the module, see
+	// ModVulns.Versions.
+	Version string
 }
 
 // A FuncNode describes a function in the call graph.
@@ -76,6 +81,10 @@ type FuncNode struct {
 	// Position describes the position of the function in the file.
 	Pos *token.Position
 
+	// Generated reports whether the function is declared in a
+	// generated file of a top-level package.
+	Generated bool
+
 	// CallSites is a set of call sites where this function is called.
 	CallSites []*CallSite
 }
@@ -120,19 +129,58 @@ type affectingVulns []*ModVulns
 type ModVulns struct {
 	Module *packages.Module
 	Vulns  []*osv.Entry
+
+	// Versions maps the IDs of the vulnerabilities in Vulns that
+	// were matched against another version of Module than its own, an
+	// assumed version or an older Go version for the standard library,
+	// to that version.
+	Versions map[string]string
+}
+
+// version returns the version of mv.Module that vulnerability id
+// affects.
+func (mv *ModVulns) version(id string) string {
+	if v, ok := mv.Versions[id]; ok {
+		return v
+	}
+	return modVersion(mv.Module)
 }
 
-func affectingVulnerabilities(vulns []*ModVulns, os, arch string) affectingVulns {
+// affectingVulnerabilities returns the vulnerabilities in vulns that affect
+// their module at its version on the os and arch platform. Modules at or
+// above their fixed version in overrides are left out. Modules with a
+// version in assumed are matched at that version instead, see
+// ModVulns.Versions. Both maps are keyed by the module path or the path of
+// its replacement, see assumedVersion. If minGo is not
+// empty, standard library vulnerabilities affecting any Go version from
+// minGo to the version of the standard library are included as well,
+// matched against the oldest affected version, see ModVulns.Versions.
+func affectingVulnerabilities(vulns []*ModVulns, os, arch string, overrides, assumed map[string]string, minGo string) affectingVulns {
 	now := time.Now()
 	var filtered affectingVulns
 	for _, mod := range vulns {
 		module := mod.Module
-		modVersion := module.Version
+		// The vulnerabilities of a module replaced by another
+		// one are those of the replacement.
+		modPath, modVersion := lookupPath(module), module.Version
 		if module.Replace != nil {
 			modVersion = module.Replace.Version
 		}
+		foundVersion := modVersion
+		if v, ok := assumedVersion(module, assumed); ok {
+			modVersion = v
+		}
+		var minVersion string
+		if module.Path == internal.GoStdModulePath {
+			minVersion = semver.GoTagToSemver(minGo)
+		}
+		if overridden(module, modVersion, overrides) {
+			// The module is trusted to be fixed locally.
+			continue
+		}
 		// TODO(https://golang.org/issues/49264): if modVersion == "", try vcs?
 		var filteredVulns []*osv.Entry
+		var versions map[string]string
 		for _, v := range mod.Vulns {
 			// Ignore vulnerabilities that have been withdrawn
 			if v.Withdrawn != nil && v.Withdrawn.Before(now) {
@@ -147,11 +195,14 @@ func affectingVulnerabilities(vulns []*ModVulns, os, arch string) affectingVulns
 				// information out as it might lead to incorrect results:
 				// Computing a latest fix could consider versions of these
 				// different packages.
-				if a.Module.Path != module.Path {
+				if a.Module.Path != modPath {
 					continue
 				}
+				matched := modVersion
 				if !affected(modVersion, a) {
-					continue
+					if matched = affectedSince(minVersion, modVersion, a); matched == "" {
+						continue
+					}
 				}
 
 				var filteredImports []osv.Package
@@ -169,6 +220,12 @@ func affectingVulnerabilities(vulns []*ModVulns, os, arch string) affectingVulns
 				}
 				a.EcosystemSpecific.Packages = filteredImports
 				filteredAffected = append(filteredAffected, a)
+				if matched != foundVersion && versions[v.ID] == "" {
+					if versions == nil {
+						versions = make(map[string]string)
+					}
+					versions[v.ID] = matched
+				}
 			}
 			if len(filteredAffected) == 0 {
 				continue
@@ -181,13 +238,39 @@ func affectingVulnerabilities(vulns []*ModVulns, os, arch string) affectingVulns
 		}
 
 		filtered = append(filtered, &ModVulns{
-			Module: module,
-			Vulns:  filteredVulns,
+			Module:   module,
+			Vulns:    filteredVulns,
+			Versions: versions,
 		})
 	}
 	return filtered
 }
 
+// assumedVersion returns the version of mod in assumed, looked up by
+// the path of mod or else of its replacement, if any.
+func assumedVersion(mod *packages.Module, assumed map[string]string) (string, bool) {
+	if v, ok := assumed[mod.Path]; ok {
+		return v, true
+	}
+	if mod.Replace != nil {
+		v, ok := assumed[mod.Replace.Path]
+		return v, ok
+	}
+	return "", false
+}
+
+// overridden reports whether version of mod is at or above the
+// version considered fixed for mod in overrides. Like for
+// assumedVersion, the fixed version is looked up by the path of
+// mod or else of its replacement, if any.
+func overridden(mod *packages.Module, version string, overrides map[string]string) bool {
+	fixed, ok := assumedVersion(mod, overrides)
+	if !ok || version == "" {
+		return false
+	}
+	return !semver.Less(version, fixed)
+}
+
 // affected checks if modVersion is affected by a:
 //   - it is included in one of the affected version ranges
 //   - and module version is not "" and "(devel)"
@@ -203,7 +286,28 @@ func affected(modVersion string, a osv.Affected) bool {
 	return semver.Affects(a.Ranges, modVersion)
 }
 
+// affectedSince returns the oldest version of the module between
+// minVersion and modVersion that is affected by a, or "" if there is
+// none or minVersion is not set.
+func affectedSince(minVersion, modVersion string, a osv.Affected) string {
+	if minVersion == "" || modVersion == "" {
+		return ""
+	}
+	return semver.AffectedBetween(a.Ranges, minVersion, modVersion)
+}
+
 func matchesPlatform(os, arch string, e osv.Package) bool {
+	if len(e.Platforms) > 0 {
+		// Explicit pairs must match exactly, except for an
+		// empty input that matches everything.
+		for _, p := range e.Platforms {
+			pos, parch, _ := strings.Cut(p, "/")
+			if (os == "" || os == pos) && (arch == "" || arch == parch) {
+				return true
+			}
+		}
+		return false
+	}
 	return matchesPlatformComponent(os, e.GOOS) &&
 		matchesPlatformComponent(arch, e.GOARCH)
 }
@@ -294,6 +398,15 @@ Vuln:
 	return packageVulns
 }
 
+// version returns the version of the module of importPath that
+// vulnerability id affects, if it is not the version of the module.
+func (aff affectingVulns) version(module, importPath, id string) string {
+	if mod := aff.moduleVulns(module, importPath); mod != nil {
+		return mod.Versions[id]
+	}
+	return ""
+}
+
 // ForSymbol returns vulnerabilities for symbol in aff.ForPackage(module, importPath).
 func (aff affectingVulns) ForSymbol(module, importPath, symbol string) []*osv.Entry {
 	vulns := aff.ForPackage(module, importPath)
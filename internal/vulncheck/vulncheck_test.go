@@ -11,7 +11,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal"
 	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
 )
 
 func TestFilterVulns(t *testing.T) {
@@ -203,7 +205,7 @@ func TestFilterVulns(t *testing.T) {
 		},
 	}
 
-	got := affectingVulnerabilities(mv, "linux", "amd64")
+	got := affectingVulnerabilities(mv, "linux", "amd64", nil, nil, "")
 	if diff := cmp.Diff(want, got, cmp.Exporter(func(t reflect.Type) bool {
 		return reflect.TypeOf(affectingVulns{}) == t || reflect.TypeOf(ModVulns{}) == t
 	})); diff != "" {
@@ -211,6 +213,295 @@ func TestFilterVulns(t *testing.T) {
 	}
 }
 
+func TestFilterVulnsPlatforms(t *testing.T) {
+	mv := []*ModVulns{
+		{
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.0.0"},
+			Vulns: []*osv.Entry{
+				// cross product: linux/amd64, windows/amd64
+				{ID: "lists", Affected: []osv.Affected{{
+					Module: osv.Module{Path: "example.mod/a"},
+					EcosystemSpecific: osv.EcosystemSpecific{Packages: []osv.Package{{
+						GOOS:   []string{"linux", "windows"},
+						GOARCH: []string{"amd64"},
+					}}},
+				}}},
+				// exact pairs: linux/amd64, windows/arm64
+				{ID: "pairs", Affected: []osv.Affected{{
+					Module: osv.Module{Path: "example.mod/a"},
+					EcosystemSpecific: osv.EcosystemSpecific{Packages: []osv.Package{{
+						Platforms: []string{"linux/amd64", "windows/arm64"},
+					}}},
+				}}},
+				// pairs take precedence over lists
+				{ID: "both", Affected: []osv.Affected{{
+					Module: osv.Module{Path: "example.mod/a"},
+					EcosystemSpecific: osv.EcosystemSpecific{Packages: []osv.Package{{
+						GOOS:      []string{"windows"},
+						GOARCH:    []string{"amd64"},
+						Platforms: []string{"linux/amd64"},
+					}}},
+				}}},
+			},
+		},
+	}
+
+	for _, test := range []struct {
+		os, arch string
+		want     []string
+	}{
+		{"linux", "amd64", []string{"lists", "pairs", "both"}},
+		{"windows", "amd64", []string{"lists"}},
+		{"windows", "arm64", []string{"pairs"}},
+		{"linux", "arm64", nil},
+		{"", "", []string{"lists", "pairs", "both"}},
+		{"windows", "", []string{"lists", "pairs"}},
+	} {
+		var got []string
+		for _, mv := range affectingVulnerabilities(mv, test.os, test.arch, nil, nil, "") {
+			for _, v := range mv.Vulns {
+				got = append(got, v.ID)
+			}
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s/%s: mismatch (-want, +got):\n%s", test.os, test.arch, diff)
+		}
+	}
+}
+
+func TestFilterVulnsOverrides(t *testing.T) {
+	vuln := func(mod string) *osv.Entry {
+		return &osv.Entry{ID: "GO-0000-0001", Affected: []osv.Affected{{
+			Module: osv.Module{Path: mod},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.5.0"}},
+			}},
+		}}}
+	}
+	mv := []*ModVulns{
+		{
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.2.0"},
+			Vulns:  []*osv.Entry{vuln("example.mod/a")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/b", Version: "v1.2.0"},
+			Vulns:  []*osv.Entry{vuln("example.mod/b")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/c", Version: "v1.2.0"},
+			Vulns:  []*osv.Entry{vuln("example.mod/c")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/d", Version: "v1.0.0",
+				Replace: &packages.Module{Path: "example.mod/dfork", Version: "v1.2.0"}},
+			Vulns: []*osv.Entry{vuln("example.mod/dfork")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/e", Version: "v1.0.0",
+				Replace: &packages.Module{Path: "example.mod/efork", Version: "v1.2.0"}},
+			Vulns: []*osv.Entry{vuln("example.mod/efork")},
+		},
+	}
+	overrides := map[string]string{
+		"example.mod/a":     "v1.2.0", // same version, cleared
+		"example.mod/b":     "v1.3.0", // newer version, still affected
+		"example.mod/d":     "v1.2.0", // by original path, cleared
+		"example.mod/efork": "v1.2.0", // by replacement path, cleared
+	}
+
+	var got []string
+	for _, v := range affectingVulnerabilities(mv, "", "", overrides, nil, "") {
+		got = append(got, v.Module.Path)
+	}
+	want := []string{"example.mod/b", "example.mod/c"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestFilterVulnsAssumedVersions(t *testing.T) {
+	vuln := func(mod string) *osv.Entry {
+		return &osv.Entry{ID: "GO-0000-0001", Affected: []osv.Affected{{
+			Module: osv.Module{Path: mod},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.5.0"}},
+			}},
+		}}}
+	}
+	mv := []*ModVulns{
+		{
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.2.0"},
+			Vulns:  []*osv.Entry{vuln("example.mod/a")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/b", Version: "v0.0.0-20200101000000-abcdefabcdef+dirty"},
+			Vulns:  []*osv.Entry{vuln("example.mod/b")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/c", Version: "v1.2.0"},
+			Vulns:  []*osv.Entry{vuln("example.mod/c")},
+		},
+		{
+			Module: &packages.Module{Path: "example.mod/d", Version: "v1.6.0",
+				Replace: &packages.Module{Path: "example.mod/e", Version: "v1.6.0"}},
+			Vulns: []*osv.Entry{vuln("example.mod/e")},
+		},
+	}
+	assumed := map[string]string{
+		"example.mod/a": "v1.5.0", // fixed version, not affected
+		"example.mod/b": "v1.4.0", // affected despite the odd version
+		"example.mod/e": "v1.3.0", // looked up by the replacement path
+	}
+
+	// The vulnerabilities are matched at the assumed versions.
+	var got []string
+	for _, v := range affectingVulnerabilities(mv, "", "", nil, assumed, "") {
+		for _, e := range v.Vulns {
+			got = append(got, v.Module.Path+"@"+v.version(e.ID))
+		}
+	}
+	want := []string{"example.mod/b@v1.4.0", "example.mod/c@v1.2.0", "example.mod/d@v1.3.0"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestFilterVulnsReplaced(t *testing.T) {
+	vuln := func(id, mod string) *osv.Entry {
+		return &osv.Entry{ID: id, Affected: []osv.Affected{{
+			Module: osv.Module{Path: mod},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.5.0"}},
+			}},
+		}}}
+	}
+	mv := []*ModVulns{
+		{
+			// The replacement is a different module, whose
+			// vulnerabilities are the ones that matter.
+			Module: &packages.Module{Path: "example.mod/a", Version: "v2.0.0",
+				Replace: &packages.Module{Path: "example.mod/fork", Version: "v1.0.0"}},
+			Vulns: []*osv.Entry{vuln("GO-0000-0001", "example.mod/fork"), vuln("GO-0000-0002", "example.mod/a")},
+		},
+		{
+			// Same module, different version.
+			Module: &packages.Module{Path: "example.mod/b", Version: "v2.0.0",
+				Replace: &packages.Module{Path: "example.mod/b", Version: "v1.0.0"}},
+			Vulns: []*osv.Entry{vuln("GO-0000-0003", "example.mod/b")},
+		},
+	}
+
+	var got []string
+	for _, v := range affectingVulnerabilities(mv, "", "", nil, nil, "") {
+		for _, e := range v.Vulns {
+			got = append(got, v.Module.Path+" "+e.ID)
+		}
+	}
+	want := []string{"example.mod/a GO-0000-0001", "example.mod/b GO-0000-0003"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestFilterVulnsMinGo(t *testing.T) {
+	vuln := func(id string, events ...osv.RangeEvent) *osv.Entry {
+		return &osv.Entry{ID: id, Affected: []osv.Affected{{
+			Module: osv.Module{Path: internal.GoStdModulePath},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: events}},
+		}}}
+	}
+	mv := []*ModVulns{
+		{
+			Module: &packages.Module{Path: internal.GoStdModulePath, Version: "v1.22.0"},
+			Vulns: []*osv.Entry{
+				vuln("GO-0000-0001", osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.19.0"}),      // fixed before go1.19
+				vuln("GO-0000-0002", osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "1.20.0"}),      // affects go1.19
+				vuln("GO-0000-0003", osv.RangeEvent{Introduced: "1.21.0"}, osv.RangeEvent{Fixed: "1.21.5"}), // affects go1.21
+				vuln("GO-0000-0004", osv.RangeEvent{Introduced: "1.22.0"}),                                  // affects go1.22
+			},
+		},
+		{
+			// minimum Go version does not apply to other modules
+			Module: &packages.Module{Path: "example.mod/a", Version: "v1.22.0"},
+			Vulns: []*osv.Entry{{ID: "GO-0000-0005", Affected: []osv.Affected{{
+				Module: osv.Module{Path: "example.mod/a"},
+				Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.20.0"}}}},
+			}}}},
+		},
+	}
+
+	for _, test := range []struct {
+		minGo string
+		want  []string // affecting vulnerabilities, at the version matched
+	}{
+		{"", []string{"GO-0000-0004@v1.22.0"}},
+		{"go1.19", []string{"GO-0000-0002@v1.19.0", "GO-0000-0003@v1.21.0", "GO-0000-0004@v1.22.0"}},
+		{"go1.21.5", []string{"GO-0000-0004@v1.22.0"}},
+	} {
+		var got []string
+		for _, v := range affectingVulnerabilities(mv, "", "", nil, nil, test.minGo) {
+			for _, e := range v.Vulns {
+				got = append(got, e.ID+"@"+v.version(e.ID))
+			}
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("minGo %q: mismatch (-want, +got):\n%s", test.minGo, diff)
+		}
+	}
+}
+
+func TestFilterVulnsMixedRanges(t *testing.T) {
+	vuln := func(id, mod string, ranges ...osv.Range) *osv.Entry {
+		return &osv.Entry{ID: id, Affected: []osv.Affected{{Module: osv.Module{Path: mod}, Ranges: ranges}}}
+	}
+	semverRange := osv.Range{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.1.0"}}}
+	ecosystem := osv.Range{Type: osv.RangeTypeEcosystem, Events: []osv.RangeEvent{{Introduced: "1.1.0"}, {Fixed: "1.3.0"}}}
+	dates := osv.Range{Type: osv.RangeTypeEcosystem, Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "2024-01-01"}}}
+	git := osv.Range{Type: osv.RangeTypeGit, Events: []osv.RangeEvent{{Introduced: "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"}}}
+	mv := []*ModVulns{{
+		Module: &packages.Module{Path: "example.mod/a", Version: "v1.2.0"},
+		Vulns: []*osv.Entry{
+			vuln("GO-0000-0001", "example.mod/a", semverRange),             // fixed
+			vuln("GO-0000-0002", "example.mod/a", ecosystem),               // affected
+			vuln("GO-0000-0003", "example.mod/a", semverRange, ecosystem),  // affected
+			vuln("GO-0000-0004", "example.mod/a", dates),                   // unknown, affected
+			vuln("GO-0000-0005", "example.mod/a", git),                     // unknown, affected
+			vuln("GO-0000-0006", "example.mod/a", dates, semverRange),      // fixed
+			vuln("GO-0000-0007", "example.mod/a", git, dates, semverRange), // fixed
+		},
+	}}
+
+	var got []string
+	for _, v := range affectingVulnerabilities(mv, "", "", nil, nil, "") {
+		for _, e := range v.Vulns {
+			got = append(got, e.ID+" "+FixedVersion(v.Module.Path, v.Module.Version, e.Affected))
+		}
+	}
+	want := []string{"GO-0000-0002 v1.3.0", "GO-0000-0003 v1.3.0", "GO-0000-0004 ", "GO-0000-0005 "}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	handler := test.NewMockHandler()
+	if err := emitRangeWarnings(handler, mv); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	for _, p := range handler.ProgressMessages {
+		warnings = append(warnings, p.Message)
+	}
+	wantWarnings := []string{
+		"warning: could not evaluate the ECOSYSTEM ranges of GO-0000-0004 for example.mod/a@v1.2.0, assuming it is affected",
+		"warning: could not evaluate the git commit ranges of GO-0000-0005 for example.mod/a@v1.2.0, assuming it is affected",
+	}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("warnings mismatch (-want, +got):\n%s", diff)
+	}
+}
+
 func TestVulnsForPackage(t *testing.T) {
 	aff := affectingVulns{
 		{
@@ -34,43 +34,120 @@ type StackEntry struct {
 }
 
 // sourceCallstacks returns representative call stacks for each
-// vulnerability in res. The returned call stacks are heuristically
-// ordered by how seemingly easy is to understand them: shorter
-// call stacks with less dynamic call sites appear earlier in the
-// returned slices.
+// vulnerability in res and, if n > 0, up to n alternative call
+// stacks for each. The alternatives are the next best candidates
+// of the same length as the representative call stack, heuristically
+// ordered by how seemingly easy is to understand them: call stacks
+// with less dynamic call sites appear earlier in the returned slices.
 //
 // sourceCallstacks performs a breadth-first search of res.CallGraph
 // starting at the vulnerable symbol and going up until reaching an entry
 // function or method in res.CallGraph.Entries. During this search,
 // each function is visited at most once to avoid potential
 // exponential explosion. Hence, not all call stacks are analyzed.
-func sourceCallstacks(res *Result) map[*Vuln]CallStack {
+func sourceCallstacks(res *Result, n int) (map[*Vuln]CallStack, map[*Vuln][]CallStack) {
 	var (
 		wg sync.WaitGroup
 		mu sync.Mutex
 	)
 	stackPerVuln := make(map[*Vuln]CallStack)
+	alternatives := make(map[*Vuln][]CallStack)
 	for _, vuln := range res.Vulns {
 		vuln := vuln
 		wg.Add(1)
 		go func() {
-			cs := sourceCallstack(vuln, res)
+			stacks := vulnCallstacks(vuln, res, n)
 			mu.Lock()
-			stackPerVuln[vuln] = cs
+			if len(stacks) > 0 {
+				stackPerVuln[vuln] = stacks[0]
+			} else {
+				stackPerVuln[vuln] = nil
+			}
+			if len(stacks) > 1 {
+				alternatives[vuln] = stacks[1:]
+			}
 			mu.Unlock()
 			wg.Done()
 		}()
 	}
 	wg.Wait()
 
-	updateInitPositions(stackPerVuln)
-	return stackPerVuln
+	// Stacks of different vulnerabilities can share init
+	// functions, so their positions are updated sequentially.
+	for _, cs := range stackPerVuln {
+		updateInitStackPositions(cs)
+	}
+	for _, stacks := range alternatives {
+		for _, cs := range stacks {
+			updateInitStackPositions(cs)
+		}
+	}
+	return stackPerVuln, alternatives
+}
+
+// vulnCallstacks returns the representative call stack of vuln
+// followed by up to n alternative call stacks, see sourceCallstacks.
+// The positions of their init functions are not updated.
+func vulnCallstacks(vuln *Vuln, res *Result, n int) []CallStack {
+	candidates := candidateCallstacks(vuln, res)
+	return candidates[:min(len(candidates), n+1)]
 }
 
-// sourceCallstack finds a representative call stack for vuln.
-// This is a shortest unique call stack with the least
-// number of dynamic call sites.
-func sourceCallstack(vuln *Vuln, res *Result) CallStack {
+// sourceEntryPoints returns, for each vulnerability in res that is
+// called, all the entry functions of res from which it is reachable.
+func sourceEntryPoints(res *Result) map[*Vuln][]*FuncNode {
+	entries := entrySet(res)
+	entryPoints := make(map[*Vuln][]*FuncNode)
+	for _, vuln := range res.Vulns {
+		if vuln.CallSink != nil {
+			entryPoints[vuln] = reachingEntries(vuln.CallSink, entries)
+		}
+	}
+	return entryPoints
+}
+
+// entrySet returns the set of entry functions of res.
+func entrySet(res *Result) map[*FuncNode]bool {
+	entries := make(map[*FuncNode]bool)
+	for _, e := range res.EntryFunctions {
+		entries[e] = true
+	}
+	return entries
+}
+
+// reachingEntries returns the functions in entries from which sink is
+// reachable, sorted by name and position. Unlike sourceCallstack, it
+// searches the whole call graph slice above sink, which makes it more
+// expensive.
+func reachingEntries(sink *FuncNode, entries map[*FuncNode]bool) []*FuncNode {
+	var reaching []*FuncNode
+	seen := map[*FuncNode]bool{sink: true}
+	queue := []*FuncNode{sink}
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		if entries[f] {
+			reaching = append(reaching, f)
+		}
+		for _, cs := range f.CallSites {
+			if !seen[cs.Parent] {
+				seen[cs.Parent] = true
+				queue = append(queue, cs.Parent)
+			}
+		}
+	}
+	sort.SliceStable(reaching, func(i, j int) bool {
+		if si, sj := reaching[i].String(), reaching[j].String(); si != sj {
+			return si < sj
+		}
+		return funcLess(reaching[i], reaching[j])
+	})
+	return reaching
+}
+
+// candidateCallstacks returns the shortest unique call stacks for
+// vuln, sorted by their number of dynamic call sites.
+func candidateCallstacks(vuln *Vuln, res *Result) []CallStack {
 	vulnSink := vuln.CallSink
 	if vulnSink == nil {
 		return nil
@@ -146,7 +223,7 @@ func sourceCallstack(vuln *Vuln, res *Result) CallStack {
 	}
 
 	// Sort candidate call stacks by their number of dynamic call
-	// sites and return the first one.
+	// sites.
 	sort.SliceStable(candidates, func(i int, j int) bool {
 		s1, s2 := candidates[i], candidates[j]
 		if w1, w2 := weight(s1), weight(s2); w1 != w2 {
@@ -159,10 +236,7 @@ func sourceCallstack(vuln *Vuln, res *Result) CallStack {
 		// search algorithm.
 		return true
 	})
-	if len(candidates) == 0 {
-		return nil
-	}
-	return candidates[0]
+	return candidates
 }
 
 // callsites picks a call site from sites for each non-visited function.
@@ -296,15 +370,13 @@ func funcLess(f1, f2 *FuncNode) bool {
 	return f1.String() < f2.String()
 }
 
-// updateInitPositions populates non-existing positions of init functions
-// and their respective calls in callStacks (see #51575).
-func updateInitPositions(callStacks map[*Vuln]CallStack) {
-	for _, cs := range callStacks {
-		for i := range cs {
-			updateInitPosition(&cs[i])
-			if i != len(cs)-1 {
-				updateInitCallPosition(&cs[i], cs[i+1])
-			}
+// updateInitStackPositions populates non-existing positions of init
+// functions and their respective calls in cs (see #51575).
+func updateInitStackPositions(cs CallStack) {
+	for i := range cs {
+		updateInitPosition(&cs[i])
+		if i != len(cs)-1 {
+			updateInitCallPosition(&cs[i], cs[i+1])
 		}
 	}
 }
@@ -397,10 +469,11 @@ func binaryCallstacks(vr *Result) map[*Vuln]CallStack {
 	callstacks := map[*Vuln]CallStack{}
 	for _, vv := range uniqueVulns(vr.Vulns) {
 		f := &FuncNode{Package: vv.Package, Name: vv.Symbol}
-		parts := strings.Split(vv.Symbol, ".")
-		if len(parts) != 1 {
-			f.RecvType = parts[0]
-			f.Name = parts[1]
+		// Placeholder symbols "<pkg-path>/*" of stripped binaries
+		// name a package, not a method, so they are not split.
+		if recv, name, ok := strings.Cut(vv.Symbol, "."); ok && !strings.HasSuffix(vv.Symbol, "/*") {
+			f.RecvType = recv
+			f.Name = name
 		}
 		callstacks[vv] = CallStack{StackEntry{Function: f}}
 	}
@@ -7,6 +7,7 @@ package vulncheck
 import (
 	"context"
 	"fmt"
+	"go/token"
 	"path"
 	"path/filepath"
 	"reflect"
@@ -66,12 +67,103 @@ func TestSourceCallstacks(t *testing.T) {
 		"vuln2": "entry2->interm2->vuln2",
 	}
 
-	stacks := sourceCallstacks(res)
+	stacks, _ := sourceCallstacks(res, 0)
 	if got := stacksToString(stacks); !reflect.DeepEqual(want, got) {
 		t.Errorf("want %v; got %v", want, got)
 	}
 }
 
+func TestAlternativeCallstacks(t *testing.T) {
+	// Call graph structure for the test program
+	//    entry1    entry2    entry3
+	//      |         |         |
+	//    interm1   interm2   interm3
+	//       \        |       /(interface)
+	//               vuln
+	// Functions have positions so that call sites are visited
+	// in a deterministic order.
+	pos := func(line int) *token.Position { return &token.Position{Filename: "p.go", Line: line} }
+	p := &packages.Package{PkgPath: "p", Module: &packages.Module{Path: "m"}}
+	e1 := &FuncNode{Name: "entry1", Package: p, Pos: pos(1)}
+	e2 := &FuncNode{Name: "entry2", Package: p, Pos: pos(2)}
+	e3 := &FuncNode{Name: "entry3", Package: p, Pos: pos(3)}
+	i1 := &FuncNode{Name: "interm1", Package: p, Pos: pos(4), CallSites: []*CallSite{{Parent: e1, Resolved: true}}}
+	i2 := &FuncNode{Name: "interm2", Package: p, Pos: pos(5), CallSites: []*CallSite{{Parent: e2, Resolved: true}}}
+	i3 := &FuncNode{Name: "interm3", Package: p, Pos: pos(6), CallSites: []*CallSite{{Parent: e3, Resolved: true}}}
+	v := &FuncNode{Name: "vuln", Package: p, CallSites: []*CallSite{
+		{Parent: i3, Resolved: false},
+		{Parent: i2, Resolved: true},
+		{Parent: i1, Resolved: true},
+	}}
+	vuln := &Vuln{CallSink: v, Package: p, OSV: &osv.Entry{ID: "o"}, Symbol: "vuln"}
+	res := &Result{
+		EntryFunctions: []*FuncNode{e1, e2, e3},
+		Vulns:          []*Vuln{vuln},
+	}
+
+	for _, test := range []struct {
+		n    int
+		want []string
+	}{
+		{0, nil},
+		{1, []string{"entry1->interm1->vuln"}},
+		{5, []string{"entry1->interm1->vuln", "entry3->interm3->vuln"}},
+	} {
+		stacks, alternatives := sourceCallstacks(res, test.n)
+		if got, want := stacksToString(stacks), map[string]string{"vuln": "entry2->interm2->vuln"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("sourceCallstacks(%d): want %v; got %v", test.n, want, got)
+		}
+		var got []string
+		for _, cs := range alternatives[vuln] {
+			got = append(got, stacksToString(map[*Vuln]CallStack{vuln: cs})["vuln"])
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("sourceCallstacks(%d) alternatives: want %v; got %v", test.n, test.want, got)
+		}
+	}
+}
+
+func TestSourceEntryPoints(t *testing.T) {
+	// Call graph structure for the test program
+	//    entry1      entry2    entry3
+	//      |           |
+	//    interm1       |
+	//      |    \     /
+	//      |     interm2
+	//      |           |
+	//     vuln1      vuln2
+	p := &packages.Package{PkgPath: "p"}
+	e1 := &FuncNode{Name: "entry1", Package: p}
+	e2 := &FuncNode{Name: "entry2", Package: p}
+	e3 := &FuncNode{Name: "entry3", Package: p}
+	i1 := &FuncNode{Name: "interm1", Package: p, CallSites: []*CallSite{{Parent: e1, Resolved: true}}}
+	i2 := &FuncNode{Name: "interm2", Package: p, CallSites: []*CallSite{{Parent: e2, Resolved: true}, {Parent: i1, Resolved: true}}}
+	v1 := &FuncNode{Name: "vuln1", Package: p, CallSites: []*CallSite{{Parent: i1, Resolved: true}}}
+	v2 := &FuncNode{Name: "vuln2", Package: p, CallSites: []*CallSite{{Parent: i2, Resolved: true}}}
+
+	o := &osv.Entry{ID: "o"}
+	vuln1 := &Vuln{CallSink: v1, Package: p, OSV: o, Symbol: "vuln1"}
+	vuln2 := &Vuln{CallSink: v2, Package: p, OSV: o, Symbol: "vuln2"}
+	res := &Result{
+		EntryFunctions: []*FuncNode{e3, e2, e1},
+		Vulns:          []*Vuln{vuln1, vuln2},
+	}
+
+	got := make(map[string][]string)
+	for v, entries := range sourceEntryPoints(res) {
+		for _, e := range entries {
+			got[v.Symbol] = append(got[v.Symbol], e.Name)
+		}
+	}
+	want := map[string][]string{
+		"vuln1": {"entry1"},
+		"vuln2": {"entry1", "entry2"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
 func TestSourceUniqueCallStack(t *testing.T) {
 	// Call graph structure for the test program
 	//    entry1      entry2
@@ -102,7 +194,7 @@ func TestSourceUniqueCallStack(t *testing.T) {
 		"vuln2": "entry2->interm1->interm2->vuln2",
 	}
 
-	stacks := sourceCallstacks(res)
+	stacks, _ := sourceCallstacks(res, 0)
 	if got := stacksToString(stacks); !reflect.DeepEqual(want, got) {
 		t.Errorf("want %v; got %v", want, got)
 	}
@@ -195,7 +287,7 @@ func TestInits(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cs := sourceCallstacks(result)
+	cs, _ := sourceCallstacks(result, 0)
 	want := map[string][]string{
 		"A": {
 			// Entry init's position is the package statement.